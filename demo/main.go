@@ -2,21 +2,17 @@ package demo
 
 import (
 	UI "github.com/onism-up/go-tml-core/tml"
+	"strings"
 )
 
 func title(text string) UI.Node {
-	node := UI.CreateQuadrilateral("title")
+	node := UI.CreateText("title") // Text nodes size themselves to their content
 
 	node.SetText(text)
 
-	node.SetVolume(UI.CanvasVolume{
-		Width:  len(text),
-		Height: 1,
-	})
-
 	node.SetPosition(UI.CanvasPosition{
 		Y: 1,
-	}, UI.CanvasPositionType{Center: UI.PositionX})
+	}, UI.CanvasAnchor{Horizontal: UI.AnchorCenter})
 
 	return node
 }
@@ -31,15 +27,8 @@ func displayNode(node UI.Node, display bool) UI.Node {
 	return node
 }
 
-func button(jumpNode UI.Node, text string, position UI.CanvasPosition, pType UI.CanvasPositionType) UI.Node {
-	node := UI.CreateQuadrilateral("button")
-	node.SetText(text)
-
-	style, _ := node.GetStyle()
-
-	style.BackGroundColor = UI.BlueBackGroundColor
-
-	node.SetStyle(style)
+func button(jumpNode UI.Node, text string) UI.Node {
+	node := UI.CreateButton("button", text)
 
 	node.SetVolume(UI.CanvasVolume{
 		Width:  20,
@@ -48,24 +37,29 @@ func button(jumpNode UI.Node, text string, position UI.CanvasPosition, pType UI.
 
 	UI.Body.Insert(jumpNode)
 
-	node.SetPosition(position, pType)
-
 	displayNode(jumpNode, false)
 
-	node.AddEventListener(UI.OnSelect, func(node UI.Node, origen UI.Node) {
-
-		if origen == jumpNode {
-			displayNode(jumpNode, false)
-			UI.Select(UI.Body)
-		} else {
-			displayNode(jumpNode, true)
-			UI.Select(jumpNode)
-		}
+	node.AddEventListener(UI.OnActivate, func(node UI.Node, origen UI.Node) { // Enter, Space or a click opens the page
+		displayNode(jumpNode, true)
+		UI.Select(jumpNode)
 	})
 
-	node.AddEventListener(UI.OnKeyBord, func(node UI.Node, origen UI.Node) {
+	return node
+}
 
-	})
+func menuBar(buttons ...UI.Node) UI.Node {
+	node := UI.CreateQuadrilateral("menu")
+	style, _ := node.GetStyle()
+
+	style.AutoSize = true
+	style.BackGroundColor = UI.YellowBackGroundColor
+	style.Layout = UI.LayoutFlex
+	style.Flex = UI.FlexContainer{Justify: UI.JustifySpaceEvenly, Align: UI.AlignCenter}
+
+	node.SetStyle(style)
+	node.SetVolume(UI.CanvasVolume{Width: UI.Auto, Height: UI.Auto})
+
+	node.Insert(buttons...)
 
 	return node
 }
@@ -80,7 +74,7 @@ func page() UI.Node {
 	position.ZIndex = 1
 
 	node.SetStyle(style)
-	node.SetPosition(position, position.Type)
+	node.SetPosition(position)
 	node.SetVolume(UI.CanvasVolume{Width: UI.Auto, Height: UI.Auto})
 
 	return node
@@ -115,7 +109,7 @@ func router1() UI.Node {
 
 	boxNode := box()
 
-	boxNode.SetPosition(UI.CanvasPosition{Y: 2, X: 1}, UI.CanvasPositionType{})
+	boxNode.SetPosition(UI.CanvasPosition{Y: 2, X: 1})
 
 	node.AddEventListener(UI.OnKeyBord, func(node UI.Node, origen UI.Node) {
 		keyBord, _ := node.GetKeyBord()
@@ -165,25 +159,10 @@ func router1() UI.Node {
 	return node
 }
 
-func text(output string, bgc, lightC string) UI.Node {
-	lenOut := len(output)
-	if lenOut > 0 {
-		childStack := UI.NodeStack{}
-		node := UI.CreateQuadrilateral("text")
-		line := 0
-		lastCut := 0
-		for i, k := range output {
-			gap := (i - lastCut)
-			if k == '\n' || (gap != 0 && UI.SysWidth != 0 && gap%UI.SysWidth == 0) { //解析转义符
-				child := textChild(output[lastCut:i], line)
-				line++
-				lastCut = i + 1
-				style, _ := child.GetStyle()
-				style.BackGroundColor = bgc
-				child.SetStyle(style)
-				childStack = append(childStack, child)
-			}
-		}
+func text(output string, bgc UI.Color) UI.Node {
+	if len(output) > 0 {
+		node := UI.CreateList("text") // Only the visible lines are drawn, the list scrolls with the arrows and the wheel by itself
+		node.SetSource(UI.StringList(wrapLines(output, UI.SysWidth)))
 
 		style, _ := node.GetStyle()
 
@@ -195,63 +174,38 @@ func text(output string, bgc, lightC string) UI.Node {
 
 		node.SetStyle(style)
 
-		index := -1
-
-		node.Insert(childStack...)
-
-		node.AddEventListener(UI.OnKeyBord, func(node UI.Node, origen UI.Node) {
-			keyBord, _ := node.GetKeyBord()
-
-			if keyBord.Key == UI.KeyArrowUp && index > 0 {
-				index--
-			} else if keyBord.Key == UI.KeyArrowDown && index < len(childStack) {
-				index++
-			} else if keyBord.Key == UI.KeyEsc {
-				parent, _ := node.GetParent()
-				UI.Select(parent)
-			}
-			for cIndex, cNode := range childStack {
-				cStyle, _ := cNode.GetStyle()
-				if cIndex == index {
-					cStyle.BackGroundColor = lightC
-				} else {
-					cStyle.BackGroundColor = bgc
-				}
-				cNode.SetStyle(cStyle)
-			}
-
-		})
-
-		return node
+		return node // The list passes Esc up to the page
 	} else {
 		return nil
 	}
 }
 
-func textChild(output string, line int) UI.Node {
-	node := UI.CreateQuadrilateral("textLine")
-	node.SetVolume(UI.CanvasVolume{Height: 1, Width: UI.Auto})
-	style, _ := node.GetStyle()
-	position, _ := node.GetPosition()
-
-	style.AutoSize = true
-	position.Y = line
-	node.SetStyle(style)
-	node.SetPosition(position)
-	node.SetText(output)
-	return node
+// wrapLines Splits the text into lines and cuts the lines longer than the width
+// @parma output: The text to split  width: The width of a line, 0 keeps the lines whole
+// @return the lines of the text
+func wrapLines(output string, width int) []string {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		chars := []rune(line)
+		for width > 0 && len(chars) > width {
+			lines = append(lines, string(chars[:width]))
+			chars = chars[width:]
+		}
+		lines = append(lines, string(chars))
+	}
+	return lines
 }
 
 func router2() UI.Node {
 	node := page()
 	style, _ := node.GetStyle()
-	jsonStr := "{\n  \"sites\": {\n    \"site\": [\n      {\n        \"id\": \"1\",\n        \"name\": \"runoob,\n        \"taps\": \"check up or down to move line height\"\n      },\n      {\n        \"id\": \"2\",\n        \"name\": \"runoobTools\",\n        \"url\": \"c.runoob.com\"\n      },\n      {\n        \"id\": \"3\",\n        \"name\": \"Google\",\n        \"taps\": \"if you want to quit,check esc\"\n      }\n    ]\n  }\n}\n"
+	jsonStr := "{\n  \"sites\": {\n    \"site\": [\n      {\n        \"id\": \"1\",\n        \"name\": \"runoob,\n        \"taps\": \"check up or down or use the mouse wheel to scroll\"\n      },\n      {\n        \"id\": \"2\",\n        \"name\": \"runoobTools\",\n        \"url\": \"c.runoob.com\"\n      },\n      {\n        \"id\": \"3\",\n        \"name\": \"Google\",\n        \"taps\": \"if you want to quit,check esc\"\n      }\n    ]\n  }\n}\n"
 
 	style.BackGroundColor = UI.RedBackGroundColor
 
 	node.SetStyle(style)
 
-	textNode := text(jsonStr, UI.RedBackGroundColor, UI.GreenBackGroundColor)
+	textNode := text(jsonStr, UI.RedBackGroundColor)
 
 	textStyle, _ := textNode.GetStyle()
 
@@ -262,9 +216,13 @@ func router2() UI.Node {
 	textNode.SetPosition(UI.CanvasPosition{Y: 3})
 
 	node.AddEventListener(UI.OnSelect, func(node UI.Node, origen UI.Node) {
-		if origen != textNode {
-			UI.Select(textNode)
-		} else {
+		UI.Select(textNode)
+	})
+
+	node.AddEventListener(UI.OnKeyBord, func(node UI.Node, origen UI.Node) { // Receives the Esc that the list does not use
+		keyBord, _ := node.GetKeyBord()
+
+		if keyBord.Key == UI.KeyEsc {
 			UI.Select(UI.Body)
 			displayNode(node, false)
 		}
@@ -310,60 +268,40 @@ func Run() {
 	style.BackGroundColor = UI.YellowBackGroundColor
 	UI.Body.SetStyle(style)
 
-	button1 := button(router1(), "MoveBox", UI.CanvasPosition{
-		X: 10,
-	}, UI.CanvasPositionType{Center: UI.PositionY})
+	button1 := button(router1(), "MoveBox")
 
-	button2 := button(router2(), "Electronic Reader", UI.CanvasPosition{
-		X: 35,
-	}, UI.CanvasPositionType{Center: UI.PositionXY})
+	button2 := button(router2(), "Electronic Reader")
 
-	button3 := button(router3(), "Awaiting development", UI.CanvasPosition{
-		X: 10,
-	}, UI.CanvasPositionType{Center: UI.PositionY, Right: UI.PositionX})
+	button3 := button(router3(), "Awaiting development")
+
+	menu := menuBar(button1, button2, button3)
 
 	buttonBase := []UI.Node{button1, button2, button3}
 
 	index := -1
 
-	UI.Body.AddEventListener(UI.OnKeyBord, func(node UI.Node, _ UI.Node) {
+	UI.Body.AddEventListener(UI.OnKeyBord, func(node UI.Node, _ UI.Node) { // The buttons pass the arrows up to the body
 		keyBord, _ := node.GetKeyBord()
 
 		switch keyBord.Key {
 		case UI.KeyArrowLeft:
 			if index > 0 {
 				index--
+			} else {
+				index = 0
 			}
-			break
 		case UI.KeyArrowRight:
 			if index < 2 {
 				index++
 			}
-			break
-		case UI.KeyEnter:
-			if index <= 2 && index >= 0 {
-				UI.Select(buttonBase[index])
-			}
-		}
-
-		for buttonIndex, buttonNode := range buttonBase {
-			buttonStyle, _ := buttonNode.GetStyle()
-
-			if buttonIndex == index {
-				buttonStyle.BackGroundColor = UI.PurpleBackGroundColor
-				buttonStyle.BorderType = UI.ContinuousLine
-				buttonStyle.BorderColor = UI.WhiteColor
-			} else {
-				buttonStyle.BackGroundColor = UI.BlueBackGroundColor
-				buttonStyle.BorderType = UI.None
-			}
-
-			buttonNode.SetStyle(buttonStyle)
+		default:
+			return
 		}
 
+		UI.Select(buttonBase[index])
 	})
 
-	UI.Body.Insert(title("<< demo: check left or right move light block >>"), button1, button2, button3)
+	UI.Body.Insert(menu, title("<< demo: check left or right to choose, enter or click to open >>"))
 	for true {
 
 	}
@@ -0,0 +1,100 @@
+package tml
+
+import (
+	"sync"
+	"time"
+)
+
+// animator is implemented by the widgets that change with time, they are ticked by every render while they are animated
+type animator interface {
+	Node
+	due(now time.Time) bool //whether the animation has a change to draw, the loop only renders while a node is due
+	tick(now time.Time)     //advances the animation, called by the render before the node is drawn
+}
+
+var (
+	animationNodes   []animator //the animated nodes
+	animationLock    sync.Mutex //guards animationNodes and animationRunning
+	animationRunning bool       //whether the animation loop is running
+)
+
+// startAnimation ticks a node in every render, the animation loop checks the nodes every AnimationTick while at least one node is animated
+// @parma node: the node to animate
+func startAnimation(node animator) {
+	animationLock.Lock()
+	defer animationLock.Unlock()
+	for _, animated := range animationNodes {
+		if animated == node {
+			return
+		}
+	}
+	animationNodes = append(animationNodes, node)
+	if !animationRunning {
+		animationRunning = true
+		go animationLoop()
+	}
+}
+
+// stopAnimation stops ticking a node
+// @parma node: the animated node
+func stopAnimation(node animator) {
+	animationLock.Lock()
+	defer animationLock.Unlock()
+	for index, animated := range animationNodes {
+		if animated == node {
+			animationNodes = append(animationNodes[:index], animationNodes[index+1:]...)
+			return
+		}
+	}
+}
+
+// animationLoop asks for a render when an animated node is due, the nodes are advanced by the render itself so their state is only
+// changed on the goroutine that draws them. Unmounted nodes stop being animated and the loop ends when no node is left
+func animationLoop() {
+	ticker := time.NewTicker(AnimationTick)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		animationLock.Lock()
+		nodes := make([]animator, 0, len(animationNodes))
+		for _, node := range animationNodes {
+			if !node.isUnMount() {
+				nodes = append(nodes, node)
+			}
+		}
+		animationNodes = nodes
+		if len(nodes) == 0 {
+			animationRunning = false
+			animationLock.Unlock()
+			return
+		}
+		animationLock.Unlock()
+
+		renderLock.Lock() // The nodes are read while no render changes them
+		due := false
+		for _, node := range nodes {
+			if node.due(now) {
+				due = true
+				break
+			}
+		}
+		renderLock.Unlock()
+
+		if due {
+			Render()
+		}
+	}
+}
+
+// advanceAnimations ticks the animated nodes before a frame is drawn
+// @parma now: the time of the frame
+func advanceAnimations(now time.Time) {
+	animationLock.Lock()
+	nodes := append([]animator{}, animationNodes...)
+	animationLock.Unlock()
+
+	for _, node := range nodes {
+		if !node.isUnMount() {
+			node.tick(now)
+		}
+	}
+}
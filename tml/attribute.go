@@ -0,0 +1,46 @@
+package tml
+
+import "strings"
+
+// TextAttribute a bitmask of the text attributes supported by the renderer
+type TextAttribute uint16
+
+// Has reports whether all the attributes of attr are set
+func (a TextAttribute) Has(attr TextAttribute) bool {
+	return a&attr == attr
+}
+
+// sgr converts the attributes to VT100 sequences, underline variants are exclusive and only the most specific one is output
+// @return the final VT100 style, empty when no attribute is set
+func (a TextAttribute) sgr() string {
+	if a == AttrNone {
+		return ""
+	}
+	strBuff := strings.Builder{}
+	if a.Has(AttrBold) {
+		strBuff.WriteString(highlight)
+	}
+	if a.Has(AttrDim) {
+		strBuff.WriteString(dim)
+	}
+	if a.Has(AttrItalic) {
+		strBuff.WriteString(italic)
+	}
+	if a.Has(AttrCurlyUnderline) {
+		strBuff.WriteString(curlyUnderline)
+	} else if a.Has(AttrDoubleUnderline) {
+		strBuff.WriteString(doubleUnderline)
+	} else if a.Has(AttrUnderline) {
+		strBuff.WriteString(underline)
+	}
+	if a.Has(AttrBlink) {
+		strBuff.WriteString(flicker)
+	}
+	if a.Has(AttrReverse) {
+		strBuff.WriteString(backDisplay)
+	}
+	if a.Has(AttrStrikethrough) {
+		strBuff.WriteString(strikethrough)
+	}
+	return strBuff.String()
+}
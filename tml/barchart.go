@@ -0,0 +1,237 @@
+package tml
+
+import (
+	"errors"
+	"math"
+)
+
+// BarChart a chart of values grouped by category, every series adds a bar to each group. Vertical bars grow up from a horizontal axis
+// with the scale on the left and the categories below, horizontal bars grow right from the categories with their value after them.
+// Bars are drawn with eighth blocks, the scale starts at zero and negative values are drawn as empty bars
+type BarChart struct {
+	Quadrilateral               //inherited struct
+	labels        []string      //the categories
+	series        []ChartSeries //the series, the value of a category is at its index
+	orientation   uint8         //BarVertical or BarHorizontal
+	barWidth      int           //cells of a vertical bar
+	showLegend    bool          //whether the names of the series are shown above the chart
+}
+
+// CreateBarChart Creates an empty vertical bar chart that fills the remaining space of its parent
+// @parma name: the name of the node, does not force uniqueness
+// @return the bar chart, loaded into each global repository before it returns
+func CreateBarChart(name string) *BarChart {
+	element := new(BarChart)
+	mountQuadrilateral(element, &element.Quadrilateral, BarChartTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFill, HeightUnit: SizeFill}
+	element.barWidth = 3
+	element.showLegend = true
+	return element
+}
+
+// SetLabels sets the categories
+// @parma labels: the categories in the order of the values
+func (bc *BarChart) SetLabels(labels ...string) error {
+	if bc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	bc.labels = labels
+	Render()
+	return nil
+}
+
+// SetSeries sets the series, several series make grouped bars
+// @parma series: the series
+func (bc *BarChart) SetSeries(series ...ChartSeries) error {
+	if bc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	bc.series = series
+	Render()
+	return nil
+}
+
+// GetSeries returns the series
+func (bc *BarChart) GetSeries() ([]ChartSeries, error) {
+	if bc.unMount {
+		return bc.series, errors.New(OperatingEmptyNodeError)
+	}
+	return bc.series, nil
+}
+
+// SetOrientation sets the direction the bars grow in
+// @parma orientation: BarVertical or BarHorizontal
+func (bc *BarChart) SetOrientation(orientation uint8) error {
+	if bc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	bc.orientation = orientation
+	Render()
+	return nil
+}
+
+// SetBarWidth sets the number of cells of a vertical bar
+// @parma width: the cells of a bar, at least 1
+func (bc *BarChart) SetBarWidth(width int) error {
+	if bc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	bc.barWidth = maxInt(width, 1)
+	Render()
+	return nil
+}
+
+// SetShowLegend sets whether the names of the series are shown above the chart
+// @parma show: whether the legend is shown
+func (bc *BarChart) SetShowLegend(show bool) error {
+	if bc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	bc.showLegend = show
+	Render()
+	return nil
+}
+
+// groupCount returns the number of categories, a series with more values than labels adds unlabeled categories
+func (bc *BarChart) groupCount() int {
+	count := len(bc.labels)
+	for _, line := range bc.series {
+		count = maxInt(count, len(line.Values))
+	}
+	return count
+}
+
+// value returns the value of a series in a category, 0 when the series has none
+// @parma series: index of the series group: index of the category
+func (bc *BarChart) value(series, group int) float64 {
+	if group >= len(bc.series[series].Values) {
+		return 0
+	}
+	return bc.series[series].Values[group]
+}
+
+// label returns the label of a category, empty when it has none
+// @parma group: index of the category
+func (bc *BarChart) label(group int) string {
+	if group >= len(bc.labels) {
+		return ""
+	}
+	return bc.labels[group]
+}
+
+// barChartRender paints the legend, the axes and the bars of a bar chart
+// @parma bc: pointer to the bar chart struct
+// @return the render result of the node
+func barChartRender(bc *BarChart) bool {
+	if bc.clip.empty() {
+		return false
+	}
+	style := bc.style
+	boxDrawing(&bc.Canvas, style)
+
+	content := bc.contentRect()
+	clip := content.intersect(bc.clip)
+	if hasLegend(bc.series, bc.showLegend) {
+		drawLegend(bc.series, content.left, content.top, style, clip)
+		content.top++
+	}
+	if len(bc.series) == 0 || content.empty() {
+		return true
+	}
+	scale := autoScale(bc.series, true, 0, 0)
+	scale.min = 0
+	if scale.max <= scale.min { // Every value is at most zero, the bars grow from zero
+		scale.max = scale.min + 1
+	}
+	if bc.orientation == BarHorizontal {
+		drawHorizontalBars(bc, content, scale, clip)
+	} else {
+		drawVerticalBars(bc, content, scale, clip)
+	}
+	return true
+}
+
+// drawVerticalBars paints the scale, the bars and the categories of a vertical bar chart, a group is as wide as its bars
+// and the groups are one cell apart
+// @parma bc: the bar chart content: the area below the legend scale: the scale of the values clip: the visible area
+func drawVerticalBars(bc *BarChart, content canvasRect, scale chartScale, clip canvasRect) {
+	style := bc.style
+	_, labelWidth := chartValueLabels(scale)
+	plot := canvasRect{left: content.left + labelWidth + 1, top: content.top, right: content.right, bottom: content.bottom - 2}
+	drawAxes(plot, &scale, style, clip)
+
+	groupWidth := bc.barWidth * len(bc.series)
+	levels := plot.height() * 8
+	for group := 0; group < bc.groupCount(); group++ {
+		left := plot.left + 1 + group*(groupWidth+1)
+		if left >= plot.right {
+			break
+		}
+		for series := range bc.series {
+			barStyle := style
+			barStyle.Color = seriesColor(bc.series[series], series)
+			level := int(math.Round(scale.fraction(bc.value(series, group)) * float64(levels)))
+			for row := 0; row < plot.height(); row++ {
+				filled := level - row*8
+				if filled <= 0 {
+					break
+				}
+				char := chartFullRune
+				if filled < 8 {
+					char = chartEighthsUp[filled-1]
+				}
+				for column := 0; column < bc.barWidth; column++ {
+					screenBuffer.set(left+series*bc.barWidth+column, plot.bottom-1-row, styleCell(barStyle, char), clip)
+				}
+			}
+		}
+		label := []rune(bc.label(group))
+		if len(label) > groupWidth {
+			label = label[:groupWidth]
+		}
+		drawChartText(string(label), left+(groupWidth-len(label))/2, plot.bottom+1, style, clip)
+	}
+}
+
+// drawHorizontalBars paints the categories, the bars and their values of a horizontal bar chart, a group has a row for every
+// series and the groups are one row apart
+// @parma bc: the bar chart content: the area below the legend scale: the scale of the values clip: the visible area
+func drawHorizontalBars(bc *BarChart, content canvasRect, scale chartScale, clip canvasRect) {
+	style := bc.style
+	labelWidth := 0
+	for group := 0; group < bc.groupCount(); group++ {
+		labelWidth = maxInt(labelWidth, len([]rune(bc.label(group))))
+	}
+	labelWidth = minInt(labelWidth, content.width()/3)
+	valueWidth := maxInt(len([]rune(formatChartValue(scale.max))), len([]rune(formatChartValue(scale.min)))) + 1
+	plot := canvasRect{left: content.left + labelWidth + 1, top: content.top, right: content.right - valueWidth, bottom: content.bottom - 1}
+	drawAxes(plot, nil, style, clip)
+
+	levels := maxInt(plot.width(), 0) * 8
+	y := plot.top
+	for group := 0; group < bc.groupCount() && y < plot.bottom; group++ {
+		label := []rune(bc.label(group))
+		if len(label) > labelWidth {
+			label = label[:labelWidth]
+		}
+		drawChartText(string(label), plot.left-1-len(label), y, style, clip)
+		for series := range bc.series {
+			barStyle := style
+			barStyle.Color = seriesColor(bc.series[series], series)
+			value := bc.value(series, group)
+			level := int(math.Round(scale.fraction(value) * float64(levels)))
+			x := plot.left
+			for ; level >= 8; level -= 8 {
+				screenBuffer.set(x, y, styleCell(barStyle, chartFullRune), clip)
+				x++
+			}
+			if level > 0 {
+				screenBuffer.set(x, y, styleCell(barStyle, progressEighths[level-1]), clip)
+				x++
+			}
+			drawChartText(formatChartValue(value), x+1, y, style, clip)
+			y++
+		}
+		y++ // A row between two groups
+	}
+}
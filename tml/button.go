@@ -0,0 +1,238 @@
+package tml
+
+import (
+	"errors"
+	"time"
+)
+
+// ButtonStyle the look of a button in one of its states, unset colors inherit the style of the node
+type ButtonStyle struct {
+	Color           Color         //text color
+	BackGroundColor Color         //background color
+	Attribute       TextAttribute //text attributes, added to the attributes of the node
+}
+
+// Button a node that shows a centered label and is activated by Enter, Space or a click, OnActivate is triggered when it is activated.
+// The button is drawn with the style of its state, see ButtonNormal
+type Button struct {
+	Quadrilateral                //inherited struct
+	disabled      bool           //whether the button ignores activations
+	pressed       bool           //whether the button is held down by the mouse
+	pressedUntil  time.Time      //a button activated by a key looks pressed until then, the renderer reads it so no timer changes the button
+	hovered       bool           //whether the mouse is over the button
+	stateStyles   [5]ButtonStyle //the style of every state, indexed by the state constants
+}
+
+// defaultButtonStyles the styles of the states of a new button
+var defaultButtonStyles = [5]ButtonStyle{
+	ButtonNormal:   {Color: WhiteColor, BackGroundColor: BlueBackGroundColor},
+	ButtonFocused:  {Color: BrightWhiteColor, BackGroundColor: BrightBlueColor, Attribute: AttrBold},
+	ButtonHovered:  {Color: BrightWhiteColor, BackGroundColor: CyanBackGroundColor},
+	ButtonPressed:  {Color: BlueColor, BackGroundColor: WhiteBackGroundColor, Attribute: AttrBold},
+	ButtonDisabled: {Color: BrightBlackColor, BackGroundColor: BlackBackGroundColor, Attribute: AttrDim},
+}
+
+// CreateButton Creates a button that is as large as its label with one cell of padding on each side
+// @parma name: the name of the node, does not force uniqueness label: the text shown on the button
+// @return the button, loaded into each global repository before it returns
+func CreateButton(name string, label string) *Button {
+	element := new(Button)
+	mountQuadrilateral(element, &element.Quadrilateral, ButtonTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFit, HeightUnit: SizeFit}
+	element.stateStyles = defaultButtonStyles
+	element.text = label
+	element.spans = PlainText(label)
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		button := node.(*Button)
+		if button.KeyEvent.Key == KeyEnter || button.KeyEvent.Key == KeySpace {
+			button.pressByKey()
+		} else {
+			forwardKey(button)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*Button).pressByMouse()
+	})
+	element.AddEventListener(OnMouseEnter, func(node Node, origen Node) {
+		node.(*Button).hover(true)
+	})
+	element.AddEventListener(OnMouseLeave, func(node Node, origen Node) {
+		node.(*Button).hover(false)
+	})
+
+	return element
+}
+
+// SetLabel sets the text shown on the button, it is the text of the node
+// @parma label: the new label
+func (b *Button) SetLabel(label string) error {
+	return b.SetText(label)
+}
+
+// GetLabel returns the text shown on the button
+func (b *Button) GetLabel() (string, error) {
+	if b.unMount {
+		return b.text, errors.New(OperatingEmptyNodeError)
+	}
+	return b.text, nil
+}
+
+// SetDisabled enables or disables the button, a disabled button is drawn with the ButtonDisabled style and ignores activations
+// @parma disabled: whether the button is disabled
+func (b *Button) SetDisabled(disabled bool) error {
+	if b.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if b.disabled != disabled {
+		b.disabled = disabled
+		b.pressed = false
+		b.pressedUntil = time.Time{}
+		Render()
+	}
+	return nil
+}
+
+// IsDisabled reports whether the button is disabled
+func (b *Button) IsDisabled() (bool, error) {
+	if b.unMount {
+		return b.disabled, errors.New(OperatingEmptyNodeError)
+	}
+	return b.disabled, nil
+}
+
+// SetStateStyle sets the style of a state of the button
+// @parma state: ButtonNormal, ButtonFocused, ButtonHovered, ButtonPressed or ButtonDisabled style: the style of the state
+func (b *Button) SetStateStyle(state uint8, style ButtonStyle) error {
+	if b.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if int(state) < len(b.stateStyles) {
+		b.stateStyles[state] = style
+		Render()
+	}
+	return nil
+}
+
+// GetStateStyle returns the style of a state of the button
+// @parma state: ButtonNormal, ButtonFocused, ButtonHovered, ButtonPressed or ButtonDisabled
+func (b *Button) GetStateStyle(state uint8) (ButtonStyle, error) {
+	if b.unMount {
+		return ButtonStyle{}, errors.New(OperatingEmptyNodeError)
+	}
+	if int(state) >= len(b.stateStyles) {
+		return ButtonStyle{}, nil
+	}
+	return b.stateStyles[state], nil
+}
+
+// GetState returns the state the button is drawn with, the disabled state comes first, then pressed, focused and hovered
+func (b *Button) GetState() (uint8, error) {
+	if b.unMount {
+		return ButtonNormal, errors.New(OperatingEmptyNodeError)
+	}
+	return b.state(), nil
+}
+
+// Activate activates the button as if it were clicked, nothing happens when it is disabled
+func (b *Button) Activate() error {
+	if b.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if !b.disabled {
+		triggerEvent(b, OnActivate, b)
+	}
+	return nil
+}
+
+// state computes the current state of the button
+func (b *Button) state() uint8 {
+	switch {
+	case b.disabled:
+		return ButtonDisabled
+	case b.pressed || time.Now().Before(b.pressedUntil):
+		return ButtonPressed
+	case SelectNode == Node(b):
+		return ButtonFocused
+	case b.hovered:
+		return ButtonHovered
+	}
+	return ButtonNormal
+}
+
+// pressByKey activates the button and shows it pressed for ButtonPressTime
+func (b *Button) pressByKey() {
+	if b.disabled {
+		return
+	}
+	b.pressedUntil = time.Now().Add(ButtonPressTime)
+	time.AfterFunc(ButtonPressTime, Render) // The frame after the press time draws the button released
+	Render()
+	b.Activate()
+}
+
+// pressByMouse follows the left button, the button is activated when the left button is released over it after being pressed on it
+func (b *Button) pressByMouse() {
+	if b.disabled || b.mouse.Button != MouseLeft {
+		return
+	}
+	switch b.mouse.Action {
+	case MousePress:
+		b.pressed = true
+		Render()
+	case MouseRelease:
+		if b.pressed {
+			b.pressed = false
+			Render()
+			b.Activate()
+		}
+	}
+}
+
+// hover follows the mouse entering and leaving the button, a press is cancelled when the mouse leaves
+// @parma hovered: whether the mouse is over the button
+func (b *Button) hover(hovered bool) {
+	b.hovered = hovered
+	if !hovered {
+		b.pressed = false
+	}
+	Render()
+}
+
+// measureContent measures the label of a button with its padding
+// @parma width: unused, the label is a single line
+// @return width and height of the content
+func (b *Button) measureContent(width int) (int, int) {
+	return b.spans.Len() + 2, 1
+}
+
+// buttonRender paints a button with the style of its state and its label in the center of the content
+// @parma b: pointer to the button struct
+// @return the render result of the node
+func buttonRender(b *Button) bool {
+	if b.clip.empty() {
+		return false
+	}
+	style := b.style
+	stateStyle := b.stateStyles[b.state()]
+	if stateStyle.Color.IsSet() {
+		style.Color = stateStyle.Color
+	}
+	if stateStyle.BackGroundColor.IsSet() {
+		style.BackGroundColor = stateStyle.BackGroundColor
+	}
+	style.Attribute |= stateStyle.Attribute
+	boxDrawing(&b.Canvas, style)
+
+	if !style.ShowText {
+		return true
+	}
+	content := b.childArea
+	label := b.spans.runes()
+	x := content.left + maxInt((content.width()-len(label))/2, 0)
+	y := content.top + (content.height()-1)/2
+	for index, labelRune := range label {
+		screenBuffer.set(x+index, y, spanCell(style, b.spans[labelRune.span], labelRune.char), b.childClip)
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+package tml
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// chartEighthsUp the partial blocks of a vertical bar from one eighth to seven eighths of a cell
+var chartEighthsUp = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇'}
+
+// chartPalette the colors of the series that do not set their own
+var chartPalette = []Color{GreenColor, CyanColor, YellowColor, PurpleColor, RedColor, BlueColor}
+
+// Chart characters
+const (
+	chartFullRune     = '█'
+	chartAxisRune     = '│'
+	chartBaseRune     = '─'
+	chartCornerRune   = '└'
+	chartLegendRune   = '■'
+	chartBrailleBlank = '⠀'
+)
+
+// ChartSeries a named sequence of values drawn by a chart
+type ChartSeries struct {
+	Name   string    //shown in the legend
+	Values []float64 //the values in the order they are drawn
+	Color  Color     //color of the series, NoColor takes a color of the palette
+}
+
+// seriesColor returns the color a series is drawn with
+// @parma series: the series index: index of the series in its chart
+func seriesColor(series ChartSeries, index int) Color {
+	if series.Color.IsSet() {
+		return series.Color
+	}
+	return chartPalette[index%len(chartPalette)]
+}
+
+// chartScale the range of values mapped to the height or the width of a plot
+type chartScale struct {
+	min float64 //value at the bottom or the left of the plot
+	max float64 //value at the top or the right of the plot
+}
+
+// autoScale measures the values of the series, a scale that includes zero starts at zero when the values are positive.
+// A fixed range is used when max is larger than min
+// @parma series: the series includeZero: whether the scale starts at zero fixedMin: minimum of a fixed range fixedMax: maximum of a fixed range
+func autoScale(series []ChartSeries, includeZero bool, fixedMin, fixedMax float64) chartScale {
+	if fixedMax > fixedMin {
+		return chartScale{min: fixedMin, max: fixedMax}
+	}
+	scale := chartScale{min: math.Inf(1), max: math.Inf(-1)}
+	for _, line := range series {
+		for _, value := range line.Values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			scale.min = math.Min(scale.min, value)
+			scale.max = math.Max(scale.max, value)
+		}
+	}
+	if math.IsInf(scale.min, 1) { // No value
+		return chartScale{min: 0, max: 1}
+	}
+	if includeZero {
+		scale.min = math.Min(scale.min, 0)
+		scale.max = math.Max(scale.max, 0)
+	}
+	if scale.max == scale.min {
+		scale.max = scale.min + 1
+	}
+	return scale
+}
+
+// fraction maps a value to its position in the scale, 0 at min and 1 at max, values outside the scale are clamped
+// @parma value: the value
+func (cs chartScale) fraction(value float64) float64 {
+	if math.IsNaN(value) {
+		return 0
+	}
+	return math.Max(math.Min((value-cs.min)/(cs.max-cs.min), 1), 0)
+}
+
+// formatChartValue formats a value of an axis in a few cells, large values use the k, M and G suffixes
+// @parma value: the value
+func formatChartValue(value float64) string {
+	suffix := ""
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e9, "G"}, {1e6, "M"}, {1e3, "k"}} {
+		if math.Abs(value) >= unit.size {
+			value /= unit.size
+			suffix = unit.suffix
+			break
+		}
+	}
+	text := strconv.FormatFloat(value, 'f', 2, 64)
+	text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	if text == "-0" {
+		text = "0"
+	}
+	return text + suffix
+}
+
+// hasLegend reports whether a legend is drawn for the series, it is drawn when it is shown and a series has a name
+// @parma series: the series show: whether the chart shows its legend
+func hasLegend(series []ChartSeries, show bool) bool {
+	if !show {
+		return false
+	}
+	for _, line := range series {
+		if line.Name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// drawLegend paints a colored square and the name of every named series on a row
+// @parma series: the series x: x-axis position of the row y: y-axis position of the row style: the style of the chart clip: the visible area
+func drawLegend(series []ChartSeries, x, y int, style CanvasStyle, clip canvasRect) {
+	for index, line := range series {
+		if line.Name == "" {
+			continue
+		}
+		markStyle := style
+		markStyle.Color = seriesColor(line, index)
+		screenBuffer.set(x, y, styleCell(markStyle, chartLegendRune), clip)
+		x += 2
+		x = drawChartText(line.Name, x, y, style, clip) + 2
+	}
+}
+
+// drawChartText paints a single line of text
+// @parma text: the text x: x-axis position of the first rune y: y-axis position style: the style of the text clip: the visible area
+// @return the cell after the text
+func drawChartText(text string, x, y int, style CanvasStyle, clip canvasRect) int {
+	for _, char := range text {
+		screenBuffer.set(x, y, styleCell(style, char), clip)
+		x++
+	}
+	return x
+}
+
+// chartValueLabels returns the labels of the top, the middle and the bottom of a vertical axis and the width of the widest one
+// @parma scale: the scale of the axis
+func chartValueLabels(scale chartScale) ([3]string, int) {
+	labels := [3]string{formatChartValue(scale.max), formatChartValue((scale.min + scale.max) / 2), formatChartValue(scale.min)}
+	width := 0
+	for _, label := range labels {
+		width = maxInt(width, len([]rune(label)))
+	}
+	return labels, width
+}
+
+// drawAxes paints a vertical axis on the left of a plot with the labels of its scale, and a horizontal axis below the plot
+// @parma plot: the plot area, the axes are drawn outside it scale: the scale of the vertical axis, nil draws no labels
+// style: the style of the chart clip: the visible area
+func drawAxes(plot canvasRect, scale *chartScale, style CanvasStyle, clip canvasRect) {
+	axisStyle := style
+	axisStyle.Attribute |= AttrDim
+	for y := plot.top; y < plot.bottom; y++ {
+		screenBuffer.set(plot.left-1, y, styleCell(axisStyle, chartAxisRune), clip)
+	}
+	screenBuffer.set(plot.left-1, plot.bottom, styleCell(axisStyle, chartCornerRune), clip)
+	for x := plot.left; x < plot.right; x++ {
+		screenBuffer.set(x, plot.bottom, styleCell(axisStyle, chartBaseRune), clip)
+	}
+	if scale == nil || plot.height() <= 0 {
+		return
+	}
+
+	labels, width := chartValueLabels(*scale)
+	rows := []int{plot.top, plot.top + (plot.height()-1)/2, plot.bottom - 1}
+	for index, row := range rows {
+		if index == 1 && (row == rows[0] || row == rows[2]) { // No room for the middle label
+			continue
+		}
+		label := labels[index]
+		drawChartText(label, plot.left-1-width+(width-len([]rune(label))), row, style, clip)
+	}
+}
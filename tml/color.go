@@ -0,0 +1,314 @@
+package tml
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Color describes a terminal color. The highest byte stores the kind of the color and the lower three bytes store its value,
+// the zero value NoColor means the color is not set and the renderer will not output anything for it
+type Color uint32
+
+// Color kind constant
+const (
+	colorKindShift              = 24
+	colorValueMask        Color = 0xFFFFFF               //Mask of the color value
+	colorKindMask         Color = 0xFF << colorKindShift //Mask of the color kind
+	colorDefaultKind      Color = 1 << colorKindShift    //The default color of the terminal
+	colorANSIKind         Color = 2 << colorKindShift    //One of the 16 ANSI colors
+	colorPaletteKind      Color = 3 << colorKindShift    //One of the 256 palette colors
+	colorRGBKind          Color = 4 << colorKindShift    //24-bit RGB color
+	ansiColorCount              = 16                     //Number of ANSI colors
+	paletteColorCount           = 256                    //Number of palette colors
+	foregroundSGR               = "3"                    //SGR prefix of the standard foreground colors
+	backgroundSGR               = "4"                    //SGR prefix of the standard background colors
+	brightForegroundSGR         = "9"                    //SGR prefix of the bright foreground colors
+	brightBackgroundSGR         = "10"                   //SGR prefix of the bright background colors
+	extendedForegroundSGR       = "38"                   //SGR prefix of the 256 and RGB foreground colors
+	extendedBackgroundSGR       = "48"                   //SGR prefix of the 256 and RGB background colors
+	defaultForegroundSGR        = vT100Basics + "39m"    //Reset the foreground to the terminal default
+	defaultBackgroundSGR        = vT100Basics + "49m"    //Reset the background to the terminal default
+)
+
+// ColorProfile describes how many colors the terminal is able to display
+type ColorProfile uint8
+
+// Color profile constant
+const (
+	AutoColorProfile    ColorProfile = 0   //Detect the profile from the environment when the framework starts
+	NoColorProfile      ColorProfile = 1   //Colors are stripped, only the text and attributes are output
+	ANSIProfile         ColorProfile = 2   //16 ANSI colors
+	ANSI256Profile      ColorProfile = 3   //256 palette colors
+	TrueColorProfile    ColorProfile = 4   //24-bit RGB colors
+	cubeThreshold                    = 48  //Values below this threshold are mapped to the first level of the color cube
+	cubeSecondThreshold              = 115 //Values below this threshold are mapped to the second level of the color cube
+)
+
+// ANSIColor creates one of the 16 ANSI colors, 0-7 are the standard colors and 8-15 are the bright colors
+// @parma index: index of the color, values out of range are wrapped
+// @return the final color
+func ANSIColor(index uint8) Color {
+	return colorANSIKind | Color(index%ansiColorCount)
+}
+
+// PaletteColor creates one of the 256 palette colors
+// @parma index: index of the color in the 256-color palette
+// @return the final color
+func PaletteColor(index uint8) Color {
+	return colorPaletteKind | Color(index)
+}
+
+// RGBColor creates a 24-bit color
+// @parma r: red g: green b: blue
+// @return the final color
+func RGBColor(r, g, b uint8) Color {
+	return colorRGBKind | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// HexColor creates a 24-bit color from a hex string, both #rgb and #rrggbb are supported and the # is optional
+// @parma hex: hex string of the color
+// @return the final color and the parse result
+func HexColor(hex string) (Color, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return NoColor, errors.New(InvalidHexColorError + hex)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return NoColor, errors.New(InvalidHexColorError + hex)
+	}
+	return colorRGBKind | Color(value), nil
+}
+
+// IsSet reports whether the color should be output by the renderer
+func (c Color) IsSet() bool {
+	return c&colorKindMask != 0
+}
+
+// IsDefault reports whether the color is the default color of the terminal
+func (c Color) IsDefault() bool {
+	return c&colorKindMask == colorDefaultKind
+}
+
+// Index returns the index of an ANSI or palette color, the result of other kinds of colors is meaningless
+func (c Color) Index() uint8 {
+	return uint8(c & 0xFF)
+}
+
+// RGB returns the red, green and blue value of the color, ANSI and palette colors are converted with the xterm default palette
+// @return red, green, blue
+func (c Color) RGB() (uint8, uint8, uint8) {
+	switch c & colorKindMask {
+	case colorRGBKind:
+		return uint8(c >> 16), uint8(c >> 8), uint8(c)
+	case colorANSIKind, colorPaletteKind:
+		return paletteToRGB(c.Index())
+	}
+	return 0, 0, 0
+}
+
+// foreground returns the VT100 sequence that sets the color as the text color, the color is degraded to the ColorMode of the terminal
+func (c Color) foreground() string {
+	return c.Degrade(ColorMode).sgr(false)
+}
+
+// background returns the VT100 sequence that sets the color as the background color, the color is degraded to the ColorMode of the terminal
+func (c Color) background() string {
+	return c.Degrade(ColorMode).sgr(true)
+}
+
+// Degrade converts the color to the nearest color that the profile is able to display
+// @parma profile: target color profile, AutoColorProfile is treated as TrueColorProfile
+// @return the degraded color, NoColor when the profile strips colors
+func (c Color) Degrade(profile ColorProfile) Color {
+	kind := c & colorKindMask
+	switch {
+	case !c.IsSet():
+		return c
+	case profile == NoColorProfile:
+		return NoColor
+	case kind == colorDefaultKind || kind == colorANSIKind:
+		return c
+	case profile == ANSIProfile:
+		if kind == colorPaletteKind && c.Index() < ansiColorCount {
+			return ANSIColor(c.Index())
+		}
+		return ANSIColor(nearestANSI(c.RGB()))
+	case profile == ANSI256Profile && kind == colorRGBKind:
+		return PaletteColor(nearestPalette(c.RGB()))
+	}
+	return c
+}
+
+// DetectColorProfile detects the color profile of the terminal from the NO_COLOR, COLORTERM and TERM environment variables
+// @return the detected color profile
+func DetectColorProfile() ColorProfile {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColorProfile
+	}
+
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return TrueColorProfile
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return NoColorProfile
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct"):
+		return TrueColorProfile
+	case strings.Contains(term, "kitty") || strings.Contains(term, "alacritty") || strings.Contains(term, "wezterm"):
+		return TrueColorProfile
+	case strings.Contains(term, "256color"):
+		return ANSI256Profile
+	}
+	return ANSIProfile
+}
+
+// nearestANSI finds the nearest color of the 16 ANSI colors
+// @parma r: red g: green b: blue
+// @return index of the ANSI color
+func nearestANSI(r, g, b uint8) uint8 {
+	var nearest uint8 = 0
+	nearestDistance := -1
+	for index, rgb := range ansiPalette {
+		distance := colorDistance(r, g, b, rgb[0], rgb[1], rgb[2])
+		if nearestDistance < 0 || distance < nearestDistance {
+			nearest = uint8(index)
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// nearestPalette finds the nearest color of the color cube and the grayscale ramp in the 256-color palette
+// @parma r: red g: green b: blue
+// @return index of the palette color
+func nearestPalette(r, g, b uint8) uint8 {
+	cr, cg, cb := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cubeColor := uint8(ansiColorCount + 36*int(cr) + 6*int(cg) + int(cb))
+	cubeDistance := colorDistance(r, g, b, cubeLevels[cr], cubeLevels[cg], cubeLevels[cb])
+
+	average := (int(r) + int(g) + int(b)) / 3
+	grayIndex := 23
+	if average < 8 {
+		grayIndex = 0
+	} else if average < 238 {
+		grayIndex = (average - 8) / 10
+	}
+	grayLevel := uint8(8 + grayIndex*10)
+	grayDistance := colorDistance(r, g, b, grayLevel, grayLevel, grayLevel)
+
+	if grayDistance < cubeDistance {
+		return uint8(232 + grayIndex)
+	}
+	return cubeColor
+}
+
+// cubeIndex auxiliary function, maps a channel value to the level of the 6x6x6 color cube
+func cubeIndex(value uint8) uint8 {
+	if value < cubeThreshold {
+		return 0
+	} else if value < cubeSecondThreshold {
+		return 1
+	}
+	return (value - 35) / 40
+}
+
+// colorDistance auxiliary function, returns the squared distance of two colors weighted by the sensitivity of the human eye
+func colorDistance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr := int(r1) - int(r2)
+	dg := int(g1) - int(g2)
+	db := int(b1) - int(b2)
+	return 3*dr*dr + 4*dg*dg + 2*db*db
+}
+
+// sgr converts the color to the select graphic rendition sequence
+// @parma background: whether the color is used as background
+// @return the final VT100 style, empty when the color is not set
+func (c Color) sgr(background bool) string {
+	strBuff := strings.Builder{}
+	switch c & colorKindMask {
+	case colorDefaultKind:
+		if background {
+			return defaultBackgroundSGR
+		}
+		return defaultForegroundSGR
+	case colorANSIKind:
+		index := int(c.Index())
+		prefix := foregroundSGR
+		if background {
+			prefix = backgroundSGR
+		}
+		if index >= 8 {
+			index -= 8
+			prefix = brightForegroundSGR
+			if background {
+				prefix = brightBackgroundSGR
+			}
+		}
+		strBuff.WriteString(vT100Basics)
+		strBuff.WriteString(prefix)
+		strBuff.WriteString(strconv.Itoa(index))
+	case colorPaletteKind:
+		strBuff.WriteString(vT100Basics)
+		strBuff.WriteString(extendedSGR(background))
+		strBuff.WriteString(";5;")
+		strBuff.WriteString(strconv.Itoa(int(c.Index())))
+	case colorRGBKind:
+		r, g, b := c.RGB()
+		strBuff.WriteString(vT100Basics)
+		strBuff.WriteString(extendedSGR(background))
+		strBuff.WriteString(";2;")
+		strBuff.WriteString(strconv.Itoa(int(r)))
+		strBuff.WriteByte(';')
+		strBuff.WriteString(strconv.Itoa(int(g)))
+		strBuff.WriteByte(';')
+		strBuff.WriteString(strconv.Itoa(int(b)))
+	default:
+		return ""
+	}
+	strBuff.WriteByte('m')
+	return strBuff.String()
+}
+
+// extendedSGR auxiliary function, returns the prefix of the 256 and RGB colors
+func extendedSGR(background bool) string {
+	if background {
+		return extendedBackgroundSGR
+	}
+	return extendedForegroundSGR
+}
+
+// ansiPalette the xterm default values of the 16 ANSI colors
+var ansiPalette = [ansiColorCount][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels the values of each axis of the 6x6x6 color cube in the 256-color palette
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteToRGB converts an index of the 256-color palette to RGB
+// @parma index: index of the palette
+// @return red, green, blue
+func paletteToRGB(index uint8) (uint8, uint8, uint8) {
+	if index < ansiColorCount {
+		rgb := ansiPalette[index]
+		return rgb[0], rgb[1], rgb[2]
+	}
+	if index >= 232 { // Grayscale ramp
+		level := 8 + (index-232)*10
+		return level, level, level
+	}
+	index -= ansiColorCount
+	return cubeLevels[index/36], cubeLevels[(index/6)%6], cubeLevels[index%6]
+}
@@ -0,0 +1,154 @@
+package tml
+
+// PromptResult the answer of a Prompt dialog
+type PromptResult struct {
+	Value string //the text typed in the dialog
+	OK    bool   //whether the dialog was accepted, false when it was cancelled or closed with Esc
+}
+
+// Alert shows a modal dialog with a message and an OK button
+// @parma title: the first line of the dialog, left out when empty message: the text of the dialog callback: called when the dialog closes, may be nil
+// @return a channel that is closed when the dialog closes, it must not be waited for by an event listener because the listeners run on the input loop
+func Alert(title, message string, callback func()) <-chan struct{} {
+	result := make(chan struct{})
+	dialog, buttons := createDialog(title, message, nil, DialogOKLabel)
+
+	done := false
+	var modal *Modal
+	finish := func() {
+		if done {
+			return
+		}
+		done = true
+		modal.Close()
+		if callback != nil {
+			callback()
+		}
+		close(result)
+	}
+	buttons[0].AddEventListener(OnActivate, func(node Node, origen Node) { finish() })
+	modal, _ = ShowModal(dialog, finish)
+	return result
+}
+
+// Confirm shows a modal dialog with a message, an OK button and a Cancel button
+// @parma title: the first line of the dialog, left out when empty message: the text of the dialog callback: receives whether OK was chosen, may be nil
+// @return a channel that receives whether OK was chosen, it must not be waited for by an event listener because the listeners run on the input loop
+func Confirm(title, message string, callback func(bool)) <-chan bool {
+	result := make(chan bool, 1)
+	dialog, buttons := createDialog(title, message, nil, DialogOKLabel, DialogCancelLabel)
+
+	done := false
+	var modal *Modal
+	finish := func(ok bool) {
+		if done {
+			return
+		}
+		done = true
+		modal.Close()
+		if callback != nil {
+			callback(ok)
+		}
+		result <- ok
+		close(result)
+	}
+	buttons[0].AddEventListener(OnActivate, func(node Node, origen Node) { finish(true) })
+	buttons[1].AddEventListener(OnActivate, func(node Node, origen Node) { finish(false) })
+	modal, _ = ShowModal(dialog, func() { finish(false) })
+	return result
+}
+
+// Prompt shows a modal dialog with a message, a text input, an OK button and a Cancel button, Enter in the input accepts the dialog
+// @parma title: the first line of the dialog, left out when empty message: the text of the dialog value: the initial text of the input
+// callback: receives the typed text and whether the dialog was accepted, may be nil
+// @return a channel that receives the answer, it must not be waited for by an event listener because the listeners run on the input loop
+func Prompt(title, message, value string, callback func(string, bool)) <-chan PromptResult {
+	result := make(chan PromptResult, 1)
+	input := CreateInput("dialogInput")
+	input.SetValue(value)
+	dialog, buttons := createDialog(title, message, input, DialogOKLabel, DialogCancelLabel)
+
+	done := false
+	var modal *Modal
+	finish := func(ok bool) {
+		if done {
+			return
+		}
+		done = true
+		answer := PromptResult{OK: ok}
+		answer.Value, _ = input.GetValue()
+		modal.Close()
+		if callback != nil {
+			callback(answer.Value, answer.OK)
+		}
+		result <- answer
+		close(result)
+	}
+	input.AddEventListener(OnSubmit, func(node Node, origen Node) { finish(true) })
+	buttons[0].AddEventListener(OnActivate, func(node Node, origen Node) { finish(true) })
+	buttons[1].AddEventListener(OnActivate, func(node Node, origen Node) { finish(false) })
+	modal, _ = ShowModal(dialog, func() { finish(false) })
+	return result
+}
+
+// createDialog builds the bordered box of a dialog: the bold title, the wrapped message, the input and a row of buttons on the right.
+// The box is as wide as its longest part within DialogMinWidth and the screen
+// @parma title: the first line, left out when empty message: the text input: the input below the message, may be nil labels: the labels of the buttons
+// @return the box and its buttons in the order of the labels
+func createDialog(title, message string, input *Input, labels ...string) (Node, []*Button) {
+	buttons := make([]*Button, len(labels))
+	buttonsWidth := 0
+	for index, label := range labels {
+		buttons[index] = CreateButton("dialogButton", label)
+		buttons[index].SetVolume(CanvasVolume{Width: len([]rune(label)) + 4, Height: 1})
+		buttonsWidth += len([]rune(label)) + 5 // One cell between two buttons
+	}
+
+	width := maxInt(maxInt(len([]rune(title)), len([]rune(message))), buttonsWidth-1)
+	width = maxInt(minInt(width, SysWidth-6), minInt(DialogMinWidth, SysWidth-4)) // The border and a margin are kept on the screen
+	width = maxInt(width, 1)
+
+	box := CreateQuadrilateral("dialog")
+	style, _ := box.GetStyle()
+	style.BorderType = ContinuousLine
+	box.SetStyle(style)
+
+	row := 0
+	if title != "" {
+		titleText := CreateText("dialogTitle")
+		titleText.SetText(title)
+		titleText.SetWrap(WrapNone)
+		titleStyle, _ := titleText.GetStyle()
+		titleStyle.Attribute |= AttrBold
+		titleText.SetStyle(titleStyle)
+		titleText.SetVolume(CanvasVolume{Width: width, Height: 1})
+		box.Insert(titleText)
+		row += 2
+	}
+
+	messageText := CreateText("dialogMessage")
+	messageText.SetText(message)
+	_, lines := messageText.measureContent(width)
+	messageText.SetVolume(CanvasVolume{Width: width, Height: lines})
+	messageText.SetPosition(CanvasPosition{Y: row})
+	box.Insert(messageText)
+	row += lines + 1
+
+	if input != nil {
+		input.SetVolume(CanvasVolume{Width: width, Height: 1})
+		input.SetPosition(CanvasPosition{Y: row})
+		box.Insert(input)
+		row += 2
+	}
+
+	x := width - buttonsWidth + 1
+	for _, button := range buttons {
+		button.SetPosition(CanvasPosition{X: x, Y: row})
+		box.Insert(button)
+		buttonVolume, _ := button.GetVolume()
+		x += buttonVolume.Width + 1
+	}
+
+	box.SetVolume(CanvasVolume{Width: width + 2, Height: row + 3})
+	return box, buttons
+}
@@ -12,6 +12,18 @@ Events that have been supported
 	OnRemove: The node is deleted.
 	OnSelect: The selected node can listen for keystroke events using the OnKeyBord event. If the node does not have a corresponding handler, the selected node will be invalid
 	OnKeyBord: Triggered when the node is pressed and the keyboard is pressed
+	OnMouse: A mouse button is pressed, released or dragged over the node, the report bubbles up to the first ancestor that listens to it
+	OnWheel: The mouse wheel is scrolled over the node, the closest scrolling node scrolls first and then the report bubbles up to the first ancestor that listens to it
+	OnScroll: The scroll offset of a scrollable node changes
+	OnMouseEnter: The mouse enters the node or one of its descendants
+	OnMouseLeave: The mouse leaves the node and all its descendants
+	OnActivate: A widget such as a button is activated
+	OnChange: The value of an editing widget is changed by the user
+	OnSubmit: The value of an editing widget is submitted with Enter
+	OnSelectItem: The selected items of a list widget change, a tree passes the selected item as the source
+	OnExpand: A tree item is expanded, the event bubbles from the item
+	OnCollapse: A tree item is collapsed, the event bubbles from the item
+	OnTabChange: The active tab of a tabs container changes, the content of the new tab is the source
 */
 
 // createEvent Add event
@@ -47,10 +59,9 @@ func addEvent(node Node, eventName uint8, callback EventCallBack) bool {
 		return false
 	}
 	attr, _ := node.GetAttr()
-	nodeEventAny, ok := eventStore.Load(attr.Key)
-
-	nodeEvent := nodeEventAny.(Event)
+	nodeEventAny, _ := eventStore.Load(attr.Key)
 
+	nodeEvent, ok := nodeEventAny.(Event) // A removed node has no events left
 	if ok {
 		callbackStack, ok := nodeEvent[eventName]
 		if ok {
@@ -73,9 +84,10 @@ func untieEvent(node Node, eventName uint8, callback EventCallBack) bool {
 		return false
 	}
 	attr, _ := node.GetAttr()
-	nodeEventAny, ok := eventStore.Load(attr.Key)
-	nodeEvent := nodeEventAny.(Event)
+	nodeEventAny, _ := eventStore.Load(attr.Key)
 	callbackKey := fmt.Sprintf("%v", callback)
+
+	nodeEvent, ok := nodeEventAny.(Event) // A removed node has no events left
 	if ok {
 		callbackStack, ok := nodeEvent[eventName]
 		if ok {
@@ -99,10 +111,9 @@ func untieEvent(node Node, eventName uint8, callback EventCallBack) bool {
 // @return Execution result
 func triggerEvent(node Node, eventName uint8, origen Node) bool {
 	attr, _ := node.GetAttr()
-	nodeEventAny, ok := eventStore.Load(attr.Key)
-
-	nodeEvent := nodeEventAny.(Event)
+	nodeEventAny, _ := eventStore.Load(attr.Key)
 
+	nodeEvent, ok := nodeEventAny.(Event) // A removed node has no events left
 	if ok {
 		callbackStack, ok := nodeEvent[eventName]
 		if ok {
@@ -117,6 +128,35 @@ func triggerEvent(node Node, eventName uint8, origen Node) bool {
 	return false
 }
 
+// hasEvent Checks whether a node listens to an event
+// @parma node: The node to check  eventName: Event type
+// @return whether at least one callback is bound
+func hasEvent(node Node, eventName uint8) bool {
+	attr, _ := node.GetAttr()
+	nodeEventAny, ok := eventStore.Load(attr.Key)
+	if !ok {
+		return false
+	}
+	callbackStack, ok := nodeEventAny.(Event)[eventName]
+	return ok && len(callbackStack) > 0
+}
+
+// bubbleEvent Triggers an event on the node or, when it does not listen to it, on the closest ancestor that does
+// @parma node: The node under the event  eventName: Event type
+// @return the node that received the event, nil when no node listens to it
+func bubbleEvent(node Node, eventName uint8) Node {
+	for target := node; target != nil; target, _ = target.GetParent() {
+		if target.isUnMount() {
+			return nil
+		}
+		if hasEvent(target, eventName) {
+			triggerEvent(target, eventName, node)
+			return target
+		}
+	}
+	return nil
+}
+
 // displayEventTrigger Node Display event notification
 // @parma node: Target node  origen: Event source node  display: Event type  deep: Whether to enable in-depth notification
 func displayEventTrigger(node Node, origen Node, display bool, deep bool) {
@@ -146,15 +186,19 @@ func autoSizeChangeTrigger(node Node, origen Node, deep bool) {
 	style, _ := node.GetStyle()
 	position, _ := node.GetPosition()
 
-	if style.AutoSize {
+	volume, _ := node.GetVolume()
+	layoutManaged := node.getCanvas().layoutManaged || volume.WidthUnit != SizeCell || volume.HeightUnit != SizeCell
+
+	if style.AutoSize || layoutManaged { // Nodes placed by a flex or grid layout or sized relative to their parent follow the size of their parent
 		triggerEvent(node, OnSizeChange, origen)
 	}
 
-	if position.Type.Center > None && position.Type.Center <= PositionXY {
+	anchored := position.Anchor.Horizontal != AnchorStart || position.Anchor.Vertical != AnchorStart
+	if anchored { // Nodes pinned to the center or the end of their parent move with it
 		triggerEvent(node, OnMove, origen)
 	}
 
-	if !style.AutoSize && position.Type.Center == None {
+	if !style.AutoSize && !layoutManaged && !anchored {
 		return
 	}
 
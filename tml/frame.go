@@ -0,0 +1,97 @@
+package tml
+
+import "strings"
+
+// cell a single character on the screen and the style it is output with
+type cell struct {
+	char            rune          //the character, a zero value is output as a space
+	color           Color         //text color
+	backGroundColor Color         //background color
+	attribute       TextAttribute //text attributes
+}
+
+// canvasBuffer the cells of a frame, every layer is painted into it by the compositor before the frame is output
+type canvasBuffer struct {
+	width   int
+	height  int
+	cells   []cell
+	cursor  bool //whether a node asked for the terminal cursor in this frame
+	cursorX int  //x-axis position of the cursor
+	cursorY int  //y-axis position of the cursor
+}
+
+// reset resizes the buffer and clears all cells
+// @parma width: width of the frame height: height of the frame
+func (cb *canvasBuffer) reset(width, height int) {
+	size := width * height
+	if cap(cb.cells) < size {
+		cb.cells = make([]cell, size)
+	} else {
+		cb.cells = cb.cells[:size]
+		for index := range cb.cells {
+			cb.cells[index] = cell{}
+		}
+	}
+	cb.width = width
+	cb.height = height
+	cb.cursor = false
+}
+
+// placeCursor shows the terminal cursor at a cell after the frame is output, the last node that places it wins
+// @parma x: x-axis position y: y-axis position clip: the visible area of the node, the cursor stays hidden outside it
+func (cb *canvasBuffer) placeCursor(x, y int, clip canvasRect) {
+	if clip.contains(x, y) && cb.get(x, y) != nil {
+		cb.cursor, cb.cursorX, cb.cursorY = true, x, y
+	}
+}
+
+// get returns the cell at the position, nil when the position is outside the frame
+func (cb *canvasBuffer) get(x, y int) *cell {
+	if x < 0 || y < 0 || x >= cb.width || y >= cb.height {
+		return nil
+	}
+	return &cb.cells[y*cb.width+x]
+}
+
+// set paints a cell, cells outside the clip or the frame are discarded
+// @parma x: x-axis position y: y-axis position c: the cell to paint clip: the visible area of the painting node
+func (cb *canvasBuffer) set(x, y int, c cell, clip canvasRect) {
+	if !clip.contains(x, y) {
+		return
+	}
+	if target := cb.get(x, y); target != nil {
+		*target = c
+	}
+}
+
+// output converts the frame to VT100 data, the style is only output when it changes between cells
+// @parma buf: the builder that receives the VT100 data
+func (cb *canvasBuffer) output(buf *strings.Builder) {
+	buf.WriteString(hiddenCursor)
+	for y := 0; y < cb.height; y++ {
+		buf.WriteString(setCursorPosition(1, uint32(y)+1))
+		var current *cell = nil
+		for x := 0; x < cb.width; x++ {
+			c := cb.get(x, y)
+			if current == nil || !c.sameStyle(current) {
+				buf.WriteString(styleSequence(c.color, c.backGroundColor, c.attribute))
+				current = c
+			}
+			if c.char == 0 {
+				buf.WriteByte(' ')
+			} else {
+				buf.WriteRune(c.char)
+			}
+		}
+	}
+	buf.WriteString(closeAllProperties)
+	if cb.cursor { // The cursor is hidden while the frame is written and shown again at the place a node asked for
+		buf.WriteString(setCursorPosition(uint32(cb.cursorX)+1, uint32(cb.cursorY)+1))
+		buf.WriteString(showCursor)
+	}
+}
+
+// sameStyle reports whether two cells are output with the same style
+func (c *cell) sameStyle(other *cell) bool {
+	return c.color == other.color && c.backGroundColor == other.backGroundColor && c.attribute == other.attribute
+}
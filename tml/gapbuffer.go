@@ -0,0 +1,77 @@
+package tml
+
+// gapBuffer a rune buffer with a gap at the last edited position, edits close to each other only move the runes between them
+type gapBuffer struct {
+	data     []rune //the runes and the gap
+	gapStart int    //index of the first cell of the gap
+	gapEnd   int    //index after the last cell of the gap
+}
+
+// Len returns the number of runes in the buffer
+func (gb *gapBuffer) Len() int {
+	return len(gb.data) - (gb.gapEnd - gb.gapStart)
+}
+
+// moveGap moves the gap in front of a rune
+// @parma position: index of the rune
+func (gb *gapBuffer) moveGap(position int) {
+	if position < gb.gapStart {
+		moved := gb.gapStart - position
+		copy(gb.data[gb.gapEnd-moved:gb.gapEnd], gb.data[position:gb.gapStart])
+		gb.gapStart -= moved
+		gb.gapEnd -= moved
+	} else if position > gb.gapStart {
+		moved := position - gb.gapStart
+		copy(gb.data[gb.gapStart:gb.gapStart+moved], gb.data[gb.gapEnd:gb.gapEnd+moved])
+		gb.gapStart += moved
+		gb.gapEnd += moved
+	}
+}
+
+// insert inserts runes in front of a rune, the buffer grows when the gap is too small
+// @parma position: index of the rune runes: the inserted runes
+func (gb *gapBuffer) insert(position int, runes []rune) {
+	gb.moveGap(position)
+	if gap := gb.gapEnd - gb.gapStart; gap < len(runes) {
+		size := maxInt(len(gb.data)*2, len(gb.data)+len(runes)-gap+64)
+		data := make([]rune, size)
+		copy(data, gb.data[:gb.gapStart])
+		tail := len(gb.data) - gb.gapEnd
+		copy(data[size-tail:], gb.data[gb.gapEnd:])
+		gb.gapEnd = size - tail
+		gb.data = data
+	}
+	copy(gb.data[gb.gapStart:], runes)
+	gb.gapStart += len(runes)
+}
+
+// delete removes the runes of a range
+// @parma start: index of the first rune end: index after the last rune
+func (gb *gapBuffer) delete(start, end int) {
+	gb.moveGap(start)
+	gb.gapEnd += end - start
+}
+
+// slice returns a copy of the runes of a range
+// @parma start: index of the first rune end: index after the last rune
+func (gb *gapBuffer) slice(start, end int) []rune {
+	runes := make([]rune, 0, end-start)
+	if start < gb.gapStart {
+		runes = append(runes, gb.data[start:minInt(end, gb.gapStart)]...)
+	}
+	if end > gb.gapStart {
+		gap := gb.gapEnd - gb.gapStart
+		runes = append(runes, gb.data[maxInt(start, gb.gapStart)+gap:end+gap]...)
+	}
+	return runes
+}
+
+// runes returns a copy of all runes
+func (gb *gapBuffer) runes() []rune {
+	return gb.slice(0, gb.Len())
+}
+
+// String returns the content of the buffer
+func (gb *gapBuffer) String() string {
+	return string(gb.runes())
+}
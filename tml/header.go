@@ -11,13 +11,42 @@ import (
 
 // Universal constant
 const (
-	Auto                int    = math.MaxInt      //Display declaration run program adaptive
-	SizeReLoadFrequency uint16 = 10               //Frequency of window query, in ms
-	BodyName                   = "body"           //The Name of the top-level node is fixed, but the node whose Name is body may have more than body. When getting body, it is recommended to use the global variable instead of the GetNodeByName function
-	QuadrilateralTag           = "quadrilateral"  //Tag of different types of nodes. Here, the tag is a quadrangle
-	ZIndexRenderType    uint8  = 0                //Different types of renderers, which can get Node through the hierarchy
-	PackageName                = "TMLRenderer"    //Package name, usually used for information printing
-	RenderLazy                 = time.Microsecond //Asynchronous wait time
+	Auto                int    = math.MaxInt            //Display declaration run program adaptive
+	SizeReLoadFrequency uint16 = 10                     //Frequency of window query, in ms
+	BodyName                   = "body"                 //The Name of the top-level node is fixed, but the node whose Name is body may have more than body. When getting body, it is recommended to use the global variable instead of the GetNodeByName function
+	QuadrilateralTag           = "quadrilateral"        //Tag of different types of nodes. Here, the tag is a quadrangle
+	ScrollViewTag              = "scrollView"           //Tag of the scrollable viewport
+	TextTag                    = "text"                 //Tag of the lightweight text node
+	ButtonTag                  = "button"               //Tag of the button
+	ButtonPressTime            = 120 * time.Millisecond //How long a button looks pressed when it is activated by a key
+	InputTag                   = "input"                //Tag of the single-line text input
+	TextAreaTag                = "textArea"             //Tag of the multi-line text editor
+	TextAreaHistory            = 1000                   //Number of edits kept by the undo history of a text area
+	ListTag                    = "list"                 //Tag of the list
+	ListTypeAheadTime          = time.Second            //How long a list waits for the next rune of a type-ahead search
+	TableTag                   = "table"                //Tag of the table
+	TreeTag                    = "tree"                 //Tag of the tree view
+	TreeItemTag                = "treeItem"             //Tag of an item of a tree view
+	TabsTag                    = "tabs"                 //Tag of the tabs container
+	ModalTag                   = "modal"                //Tag of the modal overlay
+	MenuBarTag                 = "menuBar"              //Tag of the menu bar
+	MenuTag                    = "menu"                 //Tag of a menu opened from a menu bar or a context menu
+	ProgressBarTag             = "progressBar"          //Tag of the progress bar
+	SpinnerTag                 = "spinner"              //Tag of the spinner
+	SpinnerInterval            = 80 * time.Millisecond  //How long a frame of a spinner is shown by default
+	AnimationTick              = 40 * time.Millisecond  //Interval at which the animation loop checks whether spinners and other animated widgets must be drawn again
+	SparklineTag               = "sparkline"            //Tag of the sparkline
+	SparklineHistory           = 1024                   //Number of values kept by a sparkline that values are added to
+	BarChartTag                = "barChart"             //Tag of the bar chart
+	LineChartTag               = "lineChart"            //Tag of the line chart
+	ModalZIndex         uint32 = 1 << 20                //ZIndex of the first modal, every stacked modal is one above the previous
+	DialogOKLabel              = "OK"                   //Label of the button that accepts a dialog
+	DialogCancelLabel          = "Cancel"               //Label of the button that dismisses a dialog
+	DialogMinWidth             = 24                     //Minimum width of the content of a dialog
+	ZIndexRenderType    uint8  = 0                      //Different types of renderers, which can get Node through the hierarchy
+	PackageName                = "TMLRenderer"          //Package name, usually used for information printing
+	RenderLazy                 = time.Microsecond       //Asynchronous wait time
+	EscapeDelay                = 25 * time.Millisecond  //How long a lone ESC waits for the rest of an escape sequence before it is the Esc key
 )
 
 // Universal variable
@@ -37,9 +66,21 @@ var (
 		Color:           WhiteColor,
 		BackGroundColor: BlackBackGroundColor,
 	}
-	globalBuf  = strings.Builder{}       //Final printed V100 data
-	eventStore sync.Map                  //Event storage, to prevent thread conflicts, map using sync.map map[string]Event{}
-	SelectNode Node                = nil //The currently selected node
+	screenBuffer       canvasBuffer                           //The cells of the frame, all layers are composited into it before being output
+	layoutDirty        = true                                 //Whether the cached geometry of the tree must be recomputed before the next frame
+	layoutLock         sync.Mutex                             //Keeps two layout passes from running at once, it does not guard the nodes against painting
+	globalBuf          = strings.Builder{}                    //Final printed V100 data
+	eventStore         sync.Map                               //Event storage, to prevent thread conflicts, map using sync.map map[string]Event{}
+	SelectNode         Node                = nil              //The currently selected node
+	HoverNode          Node                = nil              //The topmost node under the mouse
+	KeyModifier        uint8               = 0                //Modifier keys held with the last key, see ModShift
+	MouseEnabled                           = true             //Whether mouse reports are requested from the terminal when input events are listened to
+	mouseReporting     bool                                   //Whether listenKeyBord asked the terminal for mouse reports
+	motionReporting    bool                                   //Whether the terminal also reports the motions of the mouse without a button
+	frameFollowsMotion bool                                   //Whether a node painted in the current frame follows the motions of the mouse without a button
+	mouseLock          sync.Mutex                             //Guards the mouse reporting state of the terminal
+	widgetStore        sync.Map                               //Storage of the widgets built on a Quadrilateral Key:string value:Node
+	ColorMode          ColorProfile        = AutoColorProfile //The color profile used when outputting, detected by Start when it is AutoColorProfile, colors are degraded to it at output time
 )
 
 // style correlation constant
@@ -47,12 +88,120 @@ const (
 	None           uint8 = 0 //This style is usually not valid
 	ContinuousLine uint8 = 1 //Continuous line
 	DottedLine     uint8 = 2 //Dashed line
-	PositionX      uint8 = 1 //Used for x centering in Position
-	PositionY      uint8 = 2 //Used for y centering in Position
-	PositionXY     uint8 = 3 //Used for x and y centering in Position
 
 )
 
+// Anchor constant, the same values are used by both axes of CanvasAnchor
+const (
+	AnchorStart   uint8 = 0           //Pinned to the start edge, the offset is the distance from it
+	AnchorCenter  uint8 = 1           //Pinned to the center, the offset moves the node away from it
+	AnchorEnd     uint8 = 2           //Pinned to the end edge, the offset is the distance from it
+	AnchorStretch uint8 = 3           //Pinned to both edges, the offset is the distance from the start edge and Right/Bottom the distance from the end edge
+	AnchorLeft          = AnchorStart //Horizontal alias of AnchorStart
+	AnchorRight         = AnchorEnd   //Horizontal alias of AnchorEnd
+	AnchorTop           = AnchorStart //Vertical alias of AnchorStart
+	AnchorBottom        = AnchorEnd   //Vertical alias of AnchorEnd
+)
+
+// Size unit constant
+const (
+	SizeCell    uint8 = 0 //The value is a number of cells, Auto inherits the size of the parent when AutoSize is set
+	SizePercent uint8 = 1 //The value is a percentage of the area the node is placed in
+	SizeFill    uint8 = 2 //The value is ignored, the node fills the space left from its position to the end of its parent
+	SizeFit     uint8 = 3 //The value is ignored, the node is as large as its text and children
+)
+
+// Layout constant
+const (
+	LayoutNone          uint8 = 0 //Children are placed by their own position
+	LayoutFlex          uint8 = 1 //Children are placed by the flexbox rules of CanvasStyle.Flex
+	LayoutGrid          uint8 = 2 //Children are placed in the tracks of CanvasStyle.Grid
+	TrackFixed          uint8 = 0 //A grid track of a fixed number of cells
+	TrackFraction       uint8 = 1 //A grid track that shares the free space by weight
+	TrackAuto           uint8 = 2 //A grid track as large as its largest item
+	FlexRow             uint8 = 0 //Items are placed from left to right
+	FlexColumn          uint8 = 1 //Items are placed from top to bottom
+	FlexRowReverse      uint8 = 2 //Items are placed from right to left
+	FlexColumnReverse   uint8 = 3 //Items are placed from bottom to top
+	JustifyStart        uint8 = 0 //Items are packed at the start of the main axis
+	JustifyEnd          uint8 = 1 //Items are packed at the end of the main axis
+	JustifyCenter       uint8 = 2 //Items are packed in the center of the main axis
+	JustifySpaceBetween uint8 = 3 //The free space is put between the items
+	JustifySpaceAround  uint8 = 4 //The free space is put around every item
+	JustifySpaceEvenly  uint8 = 5 //The free space is shared evenly by the gaps before, between and after the items
+	AlignAuto           uint8 = 0 //Inherit the alignment of the container, the container itself stretches its items
+	AlignStart          uint8 = 1 //Items are placed at the start of the cross axis
+	AlignCenter         uint8 = 2 //Items are placed in the center of the cross axis
+	AlignEnd            uint8 = 3 //Items are placed at the end of the cross axis
+	AlignStretch        uint8 = 4 //Items fill the cross axis of their line
+)
+
+// Overflow constant, see CanvasStyle.Overflow
+const (
+	OverflowHidden  uint8 = 0 //The children and the text are clipped to the content area of the node
+	OverflowVisible uint8 = 1 //The children and the text may spill out of the node, they are only clipped by the ancestors
+	OverflowScroll  uint8 = 2 //The content scrolls inside the node and the scrollbar is always shown
+	OverflowAuto    uint8 = 3 //The content scrolls inside the node and the scrollbar is shown when the content is larger than the node
+	ScrollWheelStep int   = 3 //Lines scrolled by one notch of the mouse wheel
+	ScrollBarNone   uint8 = 0 //The scrollbar of a ScrollView is never shown, the content still scrolls
+	ScrollBarAuto   uint8 = 1 //The scrollbar of a ScrollView is shown when the content is larger than the viewport
+	ScrollBarAlways uint8 = 2 //The scrollbar of a ScrollView is always shown
+)
+
+// Text wrap constant
+const (
+	WrapWord uint8 = 0 //Lines are broken after the last space that fits, words longer than a line are cut
+	WrapChar uint8 = 1 //Lines are broken at the last character that fits
+	WrapNone uint8 = 2 //Lines are only broken by \n, the rest is clipped
+)
+
+// Bar chart orientation constant
+const (
+	BarVertical   uint8 = 0 //Bars grow up from the x axis
+	BarHorizontal uint8 = 1 //Bars grow right from the categories
+)
+
+// Button state constant
+const (
+	ButtonNormal   uint8 = 0 //The button is idle
+	ButtonFocused  uint8 = 1 //The button is the selected node
+	ButtonHovered  uint8 = 2 //The mouse is over the button
+	ButtonPressed  uint8 = 3 //The button is held down by the mouse or was just activated by a key
+	ButtonDisabled uint8 = 4 //The button cannot be activated
+)
+
+// Mouse constant
+const (
+	MouseNone       uint8 = 0 //No button, used by wheel and motion reports
+	MouseLeft       uint8 = 1 //Left button
+	MouseMiddle     uint8 = 2 //Middle button
+	MouseRight      uint8 = 3 //Right button
+	MousePress      uint8 = 0 //A button is pressed
+	MouseRelease    uint8 = 1 //A button is released
+	MouseMove       uint8 = 2 //The mouse moves, Button is MouseNone when no button is held
+	MouseWheelUp    uint8 = 3 //The wheel scrolls up
+	MouseWheelDown  uint8 = 4 //The wheel scrolls down
+	MouseWheelLeft  uint8 = 5 //The wheel scrolls left
+	MouseWheelRight uint8 = 6 //The wheel scrolls right
+	ModShift        uint8 = 1 //Shift is held
+	ModAlt          uint8 = 2 //Alt is held
+	ModCtrl         uint8 = 4 //Ctrl is held
+)
+
+// Text attribute constant, attributes can be combined with |
+const (
+	AttrNone            TextAttribute = 0               //No attribute
+	AttrBold            TextAttribute = 1 << (iota - 1) //Bold (highlight)
+	AttrDim                                             //Dim
+	AttrItalic                                          //Italic
+	AttrUnderline                                       //Underline
+	AttrDoubleUnderline                                 //Double underline, takes precedence over AttrUnderline
+	AttrCurlyUnderline                                  //Curly underline, takes precedence over AttrUnderline and AttrDoubleUnderline
+	AttrBlink                                           //Blink
+	AttrReverse                                         //Swap the text color and the background color
+	AttrStrikethrough                                   //Strikethrough
+)
+
 // Error information constant
 const (
 	OperatingEmptyNodeError           = "you are trying to operate a node that has been unmounted node"
@@ -61,43 +210,68 @@ const (
 	RenderUninitializedNodeError      = "trying to render an uninitialized node"
 	GetWindowSizeError                = "an attempt to get the window size failed, causing the framework to fail: "
 	ParentNodeNil                     = "The parent node is nil, and setting the parent node to nil may cause the cursor to reset"
+	InvalidHexColorError              = "invalid hex color, expected #rgb or #rrggbb: "
+	NotDescendantError                = "the node is not a descendant of the scroll view"
 )
 
 // VT100 exclusive
 const (
-	vT100Basics                = "\033["
-	closeAllProperties         = "\033[0m" //Close all properties
-	highlight                  = "\033[1m" //Set to highlight
-	underline                  = "\033[4m" //Underline
-	flicker                    = "\033[5m" //Flicker
-	backDisplay                = "\033[7m" //Reverse display
-	blanking                   = "\033[8m" //Blanking
-	left                  byte = 'D'
-	right                 byte = 'C'
-	top                   byte = 'A'
-	bottom                byte = 'B'
-	clearScreen                = "\033[2J"   //Clear screen
-	clearTheCursorEnd          = "\033[K"    //Clear the content from the cursor to the end of the line
-	saveCursor                 = "\033[s"    //Save cursor position
-	restoreCursor              = "\033[u"    //Restore cursor position
-	hiddenCursor               = "\033[?25l" //Hide cursor
-	showCursor                 = "\033[?25h" //Show cursor
-	BlackColor                 = "\033[30m"  //Black color
-	RedColor                   = "\033[31m"  //Red color
-	GreenColor                 = "\033[32m"  //Green color
-	YellowColor                = "\033[33m"  //Yellow color
-	BlueColor                  = "\033[34m"  //Blue color
-	PurpleColor                = "\033[35m"  //Purple color
-	CyanColor                  = "\033[36m"  //Cyan color
-	WhiteColor                 = "\033[37m"  //White color
-	BlackBackGroundColor       = "\033[40m"  //Black background color
-	RedBackGroundColor         = "\033[41m"  //Red background color
-	GreenBackGroundColor       = "\033[42m"  //Green background color
-	YellowBackGroundColor      = "\033[43m"  //Yellow background color
-	BlueBackGroundColor        = "\033[44m"  //Blue background color
-	PurpleBackGroundColor      = "\033[45m"  //Purple background color
-	CyanBackGroundColor        = "\033[46m"  //Cyan background color
-	WhiteBackGroundColor       = "\033[47m"  //White background color
+	vT100Basics             = "\033["
+	closeAllProperties      = "\033[0m"   //Close all properties
+	highlight               = "\033[1m"   //Set to highlight
+	dim                     = "\033[2m"   //Dim
+	italic                  = "\033[3m"   //Italic
+	underline               = "\033[4m"   //Underline
+	doubleUnderline         = "\033[4:2m" //Double underline
+	curlyUnderline          = "\033[4:3m" //Curly underline
+	flicker                 = "\033[5m"   //Flicker
+	backDisplay             = "\033[7m"   //Reverse display
+	blanking                = "\033[8m"   //Blanking
+	strikethrough           = "\033[9m"   //Strikethrough
+	left               byte = 'D'
+	right              byte = 'C'
+	top                byte = 'A'
+	bottom             byte = 'B'
+	clearScreen             = "\033[2J"                                      //Clear screen
+	clearTheCursorEnd       = "\033[K"                                       //Clear the content from the cursor to the end of the line
+	saveCursor              = "\033[s"                                       //Save cursor position
+	restoreCursor           = "\033[u"                                       //Restore cursor position
+	hiddenCursor            = "\033[?25l"                                    //Hide cursor
+	showCursor              = "\033[?25h"                                    //Show cursor
+	enableMouse             = "\033[?1000h\033[?1002h\033[?1006h"            //Report mouse buttons, drags and the wheel with SGR coordinates
+	disableMouse            = "\033[?1006l\033[?1003l\033[?1002l\033[?1000l" //Stop reporting the mouse
+	enableMotion            = "\033[?1003h"                                  //Also report the motions without a button, used while a node follows the hover
+	disableMotion           = "\033[?1003l"                                  //Only report the motions while a button is held
+)
+
+// Color constant, background colors are kept as aliases of the colors, whether a color is used as text or background is decided by the field of CanvasStyle
+const (
+	NoColor               Color = 0                  //The color is not set, nothing will be output
+	DefaultColor          Color = colorDefaultKind   //The default color of the terminal
+	BlackColor            Color = colorANSIKind | 0  //Black color
+	RedColor              Color = colorANSIKind | 1  //Red color
+	GreenColor            Color = colorANSIKind | 2  //Green color
+	YellowColor           Color = colorANSIKind | 3  //Yellow color
+	BlueColor             Color = colorANSIKind | 4  //Blue color
+	PurpleColor           Color = colorANSIKind | 5  //Purple color
+	CyanColor             Color = colorANSIKind | 6  //Cyan color
+	WhiteColor            Color = colorANSIKind | 7  //White color
+	BrightBlackColor      Color = colorANSIKind | 8  //Bright black (gray) color
+	BrightRedColor        Color = colorANSIKind | 9  //Bright red color
+	BrightGreenColor      Color = colorANSIKind | 10 //Bright green color
+	BrightYellowColor     Color = colorANSIKind | 11 //Bright yellow color
+	BrightBlueColor       Color = colorANSIKind | 12 //Bright blue color
+	BrightPurpleColor     Color = colorANSIKind | 13 //Bright purple color
+	BrightCyanColor       Color = colorANSIKind | 14 //Bright cyan color
+	BrightWhiteColor      Color = colorANSIKind | 15 //Bright white color
+	BlackBackGroundColor        = BlackColor         //Black background color
+	RedBackGroundColor          = RedColor           //Red background color
+	GreenBackGroundColor        = GreenColor         //Green background color
+	YellowBackGroundColor       = YellowColor        //Yellow background color
+	BlueBackGroundColor         = BlueColor          //Blue background color
+	PurpleBackGroundColor       = PurpleColor        //Purple background color
+	CyanBackGroundColor         = CyanColor          //Cyan background color
+	WhiteBackGroundColor        = WhiteColor         //White background color
 )
 
 // Event specific constant
@@ -110,6 +284,18 @@ const (
 	OnRemove     uint8 = 5
 	OnSelect     uint8 = 6
 	OnKeyBord    uint8 = 7
+	OnMouse      uint8 = 8
+	OnWheel      uint8 = 9
+	OnScroll     uint8 = 10
+	OnMouseEnter uint8 = 11
+	OnMouseLeave uint8 = 12
+	OnActivate   uint8 = 13
+	OnChange     uint8 = 14
+	OnSubmit     uint8 = 15
+	OnSelectItem uint8 = 16
+	OnExpand     uint8 = 17
+	OnCollapse   uint8 = 18
+	OnTabChange  uint8 = 19
 )
 
 type Key uint16
@@ -219,52 +405,113 @@ func setCursorPosition(x, y uint32) string {
 
 // CanvasPosition canvas position information, as well as its weight
 type CanvasPosition struct {
-	X      int                //x-axis position
-	Y      int                //y-axis position
-	totalX int                //including self x and x offset of all its parent nodes, any component should update this property when it is renderable, this property is crucial for most child nodes
-	totalY int                //including self y and y offset of all its parent nodes
-	Type   CanvasPositionType //different position types, such as center display
-	ZIndex uint32             //weight, the higher the display priority, the higher the weight canvas will cover the lower weight (only valid at the same level)
+	X       int          //x-axis offset from the content area of the parent, inside its border, its meaning depends on Anchor.Horizontal
+	Y       int          //y-axis offset from the content area of the parent, inside its border, its meaning depends on Anchor.Vertical
+	Anchor  CanvasAnchor //how the node is pinned to the content area of its parent, the zero value pins it to the top left corner
+	ZIndex  uint32       //weight, the higher the display priority, the higher the weight canvas will cover the lower weight (only valid at the same level unless Overlay is set)
+	Overlay bool         //opt into a global stacking context, the node is still placed relative to its parent but is painted above the whole tree in ZIndex order and is not clipped by its ancestors
 }
 
-// CanvasPositionType the type represented by position
-type CanvasPositionType struct {
-	Center uint8
-	Right  uint8
+// CanvasOverflow describes what happens to the children and the text that do not fit in a node, both axes are configured independently
+type CanvasOverflow struct {
+	Horizontal uint8 //OverflowHidden, OverflowVisible, OverflowScroll or OverflowAuto
+	Vertical   uint8 //OverflowHidden, OverflowVisible, OverflowScroll or OverflowAuto
+}
+
+// CanvasAnchor describes how a node is pinned to its parent, both axes are configured independently
+type CanvasAnchor struct {
+	Horizontal uint8 //AnchorLeft, AnchorCenter, AnchorRight or AnchorStretch
+	Vertical   uint8 //AnchorTop, AnchorCenter, AnchorBottom or AnchorStretch
+	Right      int   //distance kept from the right edge when Horizontal is AnchorStretch
+	Bottom     int   //distance kept from the bottom edge when Vertical is AnchorStretch
 }
 
 type NodeStack []Node
 
+// MouseEvent a mouse report, the position is the absolute cell under the mouse
+type MouseEvent struct {
+	X        int   //x-axis position
+	Y        int   //y-axis position
+	Button   uint8 //MouseNone, MouseLeft, MouseMiddle or MouseRight
+	Action   uint8 //MousePress, MouseRelease, MouseMove or one of the wheel actions
+	Modifier uint8 //modifier keys held, combined with | from ModShift, ModAlt and ModCtrl
+}
+
+// CanvasBounds a rectangle of cells on the screen, X and Y are absolute
+type CanvasBounds struct {
+	X      int //x-axis position of the left column
+	Y      int //y-axis position of the top row
+	Width  int //number of columns
+	Height int //number of rows
+}
+
+// CanvasLayout the geometry of a node computed by the layout pass
+type CanvasLayout struct {
+	Bounds  CanvasBounds //the rectangle occupied by the node including its border
+	Content CanvasBounds //the area inside the border, its children are placed in it
+	Visible CanvasBounds //the part of Bounds that is not clipped by the ancestors or the screen
+	Display bool         //whether the node is visible, false when it or an ancestor is hidden, unmounted, not in the tree of Body or when it is clipped out entirely
+}
+
 // CanvasVolume describes the volume parameters of canvas
 type CanvasVolume struct {
-	Width  int
-	Height int
+	Width      int   //width, its meaning depends on WidthUnit
+	Height     int   //height, its meaning depends on HeightUnit
+	WidthUnit  uint8 //unit of Width: SizeCell, SizePercent, SizeFill or SizeFit
+	HeightUnit uint8 //unit of Height: SizeCell, SizePercent, SizeFill or SizeFit
+	MinWidth   int   //minimum width in cells, 0 means no minimum
+	MaxWidth   int   //maximum width in cells, 0 means no maximum
+	MinHeight  int   //minimum height in cells, 0 means no minimum
+	MaxHeight  int   //maximum height in cells, 0 means no maximum
 }
 
 // CanvasStyle describes the style
 type CanvasStyle struct {
-	Display         bool   //whether to display, not delete
-	AutoSize        bool   //adaptive size, its size inherits from the parent element, and will be overwritten by valid values when volume's width\height is not equal to 0
-	BorderType      uint8  //whether to display border, and
-	BorderColor     string //border color
-	Color           string //text color
-	BackGroundColor string //background color
-	ShowText        bool   //whether to display text
+	Display         bool           //whether to display, not delete
+	AutoSize        bool           //adaptive size, its size inherits from the parent element, and will be overwritten by valid values when volume's width\height is not equal to 0
+	BorderType      uint8          //whether to display border, and
+	BorderColor     Color          //border color
+	Color           Color          //text color
+	BackGroundColor Color          //background color
+	ShowText        bool           //whether to display text
+	Attribute       TextAttribute  //text attributes such as bold or underline, applied to the text and the border
+	Overflow        CanvasOverflow //how the children and the text that do not fit in the node are shown, an axis left OverflowHidden keeps the overflow of the widget, such as the scrolling of a ScrollView
+	Layout          uint8          //how the children are placed, LayoutNone leaves them at their own position
+	Flex            FlexContainer  //properties of the flex container, valid when Layout is LayoutFlex
+	FlexItem        FlexItem       //properties of the node when its parent is a flex container
+	Grid            GridContainer  //tracks of the grid container, valid when Layout is LayoutGrid
+	GridItem        GridItem       //cells of the node when its parent is a grid container
 }
 
 // Canvas  main body
 type Canvas struct {
-	name     string         //used for identification, but not unique
-	tag      string         //tag
-	key      string         //unique key
-	position CanvasPosition //position and weight information
-	volume   CanvasVolume   //volume information
-	style    CanvasStyle    //style
-	parent   Node           //parent node
-	children []Node         //child nodes
-	unMount  bool           //whether to unmount
-	text     string         //the text data that Node needs to render, and should show as much as possible when it can be displayed
+	self          Node           //the node that embeds the Canvas, widgets built on a Quadrilateral are passed to events and parents as themselves
+	name          string         //used for identification, but not unique
+	tag           string         //tag
+	key           string         //unique key
+	position      CanvasPosition //position and weight information
+	volume        CanvasVolume   //volume information
+	style         CanvasStyle    //style
+	parent        Node           //parent node
+	children      []Node         //child nodes
+	unMount       bool           //whether to unmount
+	text          string         //the text data that Node needs to render, and should show as much as possible when it can be displayed
+	spans         StyledText     //the styled spans of text, text is always the plain content of spans
+	rect          canvasRect     //the rectangle of the node on the screen computed by the last layout pass
+	childArea     canvasRect     //the area the children are placed in, the content area unless the node scrolls
+	childClip     canvasRect     //the area the children and the text are clipped to, empty when they are not visible
+	scroll        scrollState    //scroll offsets and viewport of a node that scrolls its content, see CanvasStyle.Overflow
+	overflow      CanvasOverflow //the overflow of a widget, used on the axes its style leaves OverflowHidden so SetStyle does not turn scrolling off
+	clip          canvasRect     //the visible part of rect left by the ancestors of the node
+	displayed     bool           //whether the last layout pass found the node visible
+	layoutRect    canvasRect     //the rectangle computed by the layout of the parent
+	layoutManaged bool           //whether the node is placed by the layout of its parent instead of its position
+
+}
 
+// getCanvas returns the Canvas itself, every node embeds a Canvas so the internal pipeline can reach the shared fields
+func (c *Canvas) getCanvas() *Canvas {
+	return c
 }
 
 // CanvasAttr some key information of Canvas
@@ -281,27 +528,34 @@ type Event = map[uint8][]EventCallBack
 
 // Node the common interface that elements need to implement
 type Node interface {
-	Insert(node ...Node) error                                              //insert elements into the current node
-	SetVolume(volume CanvasVolume) error                                    //set the volume by the Volume field
-	SetPosition(position CanvasPosition, pType ...CanvasPositionType) error //set the Position field related properties
-	SetStyle(style CanvasStyle) error                                       //set the Style related properties
-	GetVolume() (CanvasVolume, error)                                       //return the current node's related volume
-	GetPosition() (CanvasPosition, error)                                   //get the Position field related information
-	GetStyle() (CanvasStyle, error)                                         //get the style
-	GetProps() (map[string]string, error)                                   //get the Props
-	SetProps(key, value string) error                                       //set the Props custom field
-	GetKeyBord() (keyboard.KeyEvent, error)                                 //try to get the keyboard event value of the current node (only accurate when obtained in the event)
-	AddEventListener(event uint8, callback EventCallBack) error             //add event listener
-	DeleteEventListener(event uint8, callback EventCallBack) error          //delete event listener
-	GetAttr() (CanvasAttr, error)                                           //get the Attr field related information
-	RemoveChildren(node Node) error                                         //delete a child node
-	GetParent() (Node, error)                                               //get the parent node
-	GetChildren() ([]Node, error)                                           //get all child nodes
-	isUnMount() bool                                                        //check if unmounted
-	Remove() error                                                          //self-delete
-	setParent(node Node) error                                              //set the parent node of Node
-	SetText(text string) error                                              //set text
-	setKeyBord(keyboard.KeyEvent)                                           //set key bord
+	Insert(node ...Node) error                                         //insert elements into the current node
+	SetVolume(volume CanvasVolume) error                               //set the volume by the Volume field
+	SetPosition(position CanvasPosition, anchor ...CanvasAnchor) error //set the Position field related properties, the last anchor overrides position.Anchor
+	SetStyle(style CanvasStyle) error                                  //set the Style related properties
+	GetVolume() (CanvasVolume, error)                                  //return the current node's related volume
+	GetPosition() (CanvasPosition, error)                              //get the Position field related information
+	GetStyle() (CanvasStyle, error)                                    //get the style
+	GetProps() (map[string]string, error)                              //get the Props
+	SetProps(key, value string) error                                  //set the Props custom field
+	GetKeyBord() (keyboard.KeyEvent, error)                            //try to get the keyboard event value of the current node (only accurate when obtained in the event)
+	AddEventListener(event uint8, callback EventCallBack) error        //add event listener
+	DeleteEventListener(event uint8, callback EventCallBack) error     //delete event listener
+	GetAttr() (CanvasAttr, error)                                      //get the Attr field related information
+	RemoveChildren(node Node) error                                    //delete a child node
+	GetParent() (Node, error)                                          //get the parent node
+	GetChildren() ([]Node, error)                                      //get all child nodes
+	isUnMount() bool                                                   //check if unmounted
+	Remove() error                                                     //self-delete
+	setParent(node Node) error                                         //set the parent node of Node
+	SetText(text string) error                                         //set text
+	SetStyledText(text StyledText) error                               //set text made up of spans with their own styles, see ParseMarkup
+	GetText() (StyledText, error)                                      //get the styled text
+	setKeyBord(keyboard.KeyEvent)                                      //set key bord
+	GetMouse() (MouseEvent, error)                                     //try to get the mouse event value of the current node (only accurate when obtained in the event)
+	setMouse(MouseEvent)                                               //set mouse
+	GetLayout() (CanvasLayout, error)                                  //get the geometry computed by the layout pass, the layout is recomputed first when it is outdated
+	GetBounds() (CanvasBounds, error)                                  //get the rectangle the node occupies on the screen
+	getCanvas() *Canvas                                                //get the Canvas of the node for the internal pipeline
 }
 
 // RenderZIndexTree A hierarchy tree generated from the RenderStack
@@ -0,0 +1,417 @@
+package tml
+
+import (
+	"github.com/eiannone/keyboard"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// inputEvent a key or a mouse report decoded from the terminal input
+type inputEvent struct {
+	key      keyboard.KeyEvent //the key, valid when mouse is nil
+	modifier uint8             //modifier keys held with the key
+	mouse    *MouseEvent       //the mouse report, nil for keys
+}
+
+// inputReader decodes the bytes read from the terminal, sequences split between two reads are kept until they are complete
+type inputReader struct {
+	pending []byte
+}
+
+// csiKeys the keys of the CSI sequences that end with a letter
+var csiKeys = map[byte]keyboard.Key{
+	'A': KeyArrowUp,
+	'B': KeyArrowDown,
+	'C': KeyArrowRight,
+	'D': KeyArrowLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+// tildeKeys the keys of the CSI sequences that end with ~, indexed by their first parameter
+var tildeKeys = map[int]keyboard.Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPgup,
+	6:  KeyPgdn,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// feed decodes the bytes of a read
+// @parma data: the bytes read from the terminal
+// @return the complete events in order
+func (ir *inputReader) feed(data []byte) []inputEvent {
+	ir.pending = append(ir.pending, data...)
+	events := []inputEvent{}
+	for len(ir.pending) > 0 {
+		size, event, ok := decodeInput(ir.pending)
+		if size == 0 { // Wait for the rest of the sequence
+			break
+		}
+		ir.pending = ir.pending[size:]
+		if ok {
+			events = append(events, event)
+		}
+	}
+	if len(ir.pending) == 0 {
+		ir.pending = nil
+	}
+	return events
+}
+
+// flush decodes the pending input when no more bytes arrived in time, a pending escape is the Esc key and an incomplete rune is dropped
+// @return the events in order
+func (ir *inputReader) flush() []inputEvent {
+	events := []inputEvent{}
+	for len(ir.pending) > 0 {
+		if ir.pending[0] != '\033' {
+			ir.pending = nil
+			break
+		}
+		events = append(events, inputEvent{key: keyboard.KeyEvent{Key: KeyEsc}})
+		ir.pending = ir.pending[1:]
+		events = append(events, ir.feed(nil)...) // The bytes after the escape are decoded on their own
+	}
+	return events
+}
+
+// decodeInput decodes the first event of the input
+// @parma buf: the pending input
+// @return the number of bytes used, 0 when the event is incomplete, the event and whether the bytes form a known event
+func decodeInput(buf []byte) (int, inputEvent, bool) {
+	if buf[0] == '\033' {
+		if len(buf) == 1 { // The rest of a sequence may follow, a lone escape is resolved by flush
+			return 0, inputEvent{}, false
+		}
+		if buf[1] == '\033' { // An escape followed by another one is the Esc key
+			return 1, inputEvent{key: keyboard.KeyEvent{Key: KeyEsc}}, true
+		}
+		switch buf[1] {
+		case '[':
+			return decodeCSI(buf)
+		case 'O':
+			if len(buf) < 3 {
+				return 0, inputEvent{}, false
+			}
+			key, ok := csiKeys[buf[2]]
+			return 3, inputEvent{key: keyboard.KeyEvent{Key: key}}, ok
+		}
+		if key := keyboard.Key(buf[1]); key <= KeySpace || key == KeyBackspace2 { // Alt with a functional key is that key with ModAlt, not a rune
+			return 2, inputEvent{key: keyboard.KeyEvent{Key: key}, modifier: ModAlt}, true
+		}
+		char, size := utf8.DecodeRune(buf[1:]) // Alt combinations are reported as Esc with the rune, like the keyboard package does
+		if char == utf8.RuneError && !utf8.FullRune(buf[1:]) {
+			return 0, inputEvent{}, false
+		}
+		return size + 1, inputEvent{key: keyboard.KeyEvent{Key: KeyEsc, Rune: char}, modifier: ModAlt}, true
+	}
+
+	if keyboard.Key(buf[0]) <= KeySpace || keyboard.Key(buf[0]) == KeyBackspace2 { // Functional keys
+		return 1, inputEvent{key: keyboard.KeyEvent{Key: keyboard.Key(buf[0])}}, true
+	}
+
+	if !utf8.FullRune(buf) {
+		return 0, inputEvent{}, false
+	}
+	char, size := utf8.DecodeRune(buf)
+	return size, inputEvent{key: keyboard.KeyEvent{Rune: char}}, char != utf8.RuneError
+}
+
+// decodeCSI decodes a sequence that starts with ESC [
+// @parma buf: the pending input
+// @return the number of bytes used, the event and whether the sequence is known
+func decodeCSI(buf []byte) (int, inputEvent, bool) {
+	end := 2
+	for end < len(buf) && (buf[end] < 0x40 || buf[end] > 0x7E) {
+		end++
+	}
+	if end == len(buf) {
+		if len(buf) > 32 { // Not a sequence that will ever be complete
+			return len(buf), inputEvent{}, false
+		}
+		return 0, inputEvent{}, false
+	}
+	size := end + 1
+	params := string(buf[2:end])
+	final := buf[end]
+
+	if strings.HasPrefix(params, "<") { // SGR mouse report
+		mouse, ok := decodeMouse(params[1:], final)
+		return size, inputEvent{mouse: &mouse}, ok
+	}
+
+	numbers := []int{}
+	for _, param := range strings.Split(params, ";") {
+		number, _ := strconv.Atoi(param)
+		numbers = append(numbers, number)
+	}
+	event := inputEvent{}
+	if len(numbers) > 1 && numbers[1] > 1 {
+		event.modifier = uint8(numbers[1] - 1)
+	}
+
+	switch final {
+	case '~':
+		key, ok := tildeKeys[numbers[0]]
+		event.key.Key = key
+		return size, event, ok
+	case 'Z': // Shift+Tab
+		event.key.Key = KeyTab
+		event.modifier |= ModShift
+		return size, event, true
+	}
+	key, ok := csiKeys[final]
+	event.key.Key = key
+	return size, event, ok
+}
+
+// decodeMouse decodes the parameters of an SGR mouse report
+// @parma params: button code, column and row separated by ; final: M for a press or a motion, m for a release
+// @return the mouse report and whether the parameters are valid
+func decodeMouse(params string, final byte) (MouseEvent, bool) {
+	fields := strings.Split(params, ";")
+	if len(fields) != 3 || (final != 'M' && final != 'm') {
+		return MouseEvent{}, false
+	}
+	code, err := strconv.Atoi(fields[0])
+	x, xErr := strconv.Atoi(fields[1])
+	y, yErr := strconv.Atoi(fields[2])
+	if err != nil || xErr != nil || yErr != nil {
+		return MouseEvent{}, false
+	}
+
+	mouse := MouseEvent{X: x - 1, Y: y - 1}
+	if code&4 != 0 {
+		mouse.Modifier |= ModShift
+	}
+	if code&8 != 0 {
+		mouse.Modifier |= ModAlt
+	}
+	if code&16 != 0 {
+		mouse.Modifier |= ModCtrl
+	}
+
+	button := uint8(code & 3)
+	switch {
+	case code&64 != 0: // Wheel notches are reported as the buttons 4 to 7
+		mouse.Action = MouseWheelUp + button
+	case final == 'm':
+		mouse.Action = MouseRelease
+		mouse.Button = button + 1
+	case code&32 != 0:
+		mouse.Action = MouseMove
+		if button < 3 {
+			mouse.Button = button + 1
+		}
+	default:
+		mouse.Action = MousePress
+		mouse.Button = button + 1
+	}
+	return mouse, true
+}
+
+// dispatchKey delivers a key to the selected node, the selection rolls back when the node does not listen to keys
+// @parma event: the key modifier: the modifier keys held with the key, listeners read it from KeyModifier
+func dispatchKey(event keyboard.KeyEvent, modifier uint8) {
+	KeyModifier = modifier
+	if SelectNode == nil {
+		SelectNode = Body
+	}
+	if top := TopModal(); top != nil && !nodePath(SelectNode).contains(top) { // Keys never reach the nodes beneath a modal
+		SelectNode = top
+	} else if top == nil && menuShortcut(event, modifier) { // Menu bars see the keys first
+		return
+	}
+
+	SelectNode.setKeyBord(event)
+
+	if SelectNode.isUnMount() || !triggerEvent(SelectNode, OnKeyBord, SelectNode) { // Try to detect whether the node is invalid, roll back when it is
+		backSelect(SelectNode)
+	}
+}
+
+// dispatchMouse delivers a mouse report to the topmost node under the mouse, a left press also selects the closest node that listens to keys
+// @parma event: the mouse report
+func dispatchMouse(event MouseEvent) {
+	target := hitTest(event.X, event.Y)
+	if target == nil {
+		return
+	}
+	for node := target; node != nil; node, _ = node.GetParent() { // Every node that the report may bubble to can read it
+		node.setMouse(event)
+	}
+	updateHover(target)
+
+	if event.Action >= MouseWheelUp { // The closest scrolling node scrolls, then the listeners are told
+		wheelScroll(target, event)
+		bubbleEvent(target, OnWheel)
+		return
+	}
+
+	if event.Action == MousePress && event.Button == MouseLeft {
+		for node := target; node != nil; node, _ = node.GetParent() {
+			if hasEvent(node, OnKeyBord) {
+				if node != SelectNode {
+					Select(node)
+				}
+				break
+			}
+		}
+	}
+	bubbleEvent(target, OnMouse)
+}
+
+// motionFollower is implemented by the widgets that follow the motions of the mouse without a button, such as menus
+type motionFollower interface {
+	followsMotion() bool
+}
+
+// followsMotion reports whether a node needs the motions of the mouse without a button, which are the nodes that listen to
+// OnMouseEnter or OnMouseLeave and the widgets that follow the mouse by themselves
+// @parma node: the node
+func followsMotion(node Node) bool {
+	if follower, ok := node.(motionFollower); ok && follower.followsMotion() {
+		return true
+	}
+	return hasEvent(node, OnMouseEnter) || hasEvent(node, OnMouseLeave)
+}
+
+// setMouseReporting asks the terminal to start or stop reporting the mouse
+// @parma enabled: whether the mouse is reported
+func setMouseReporting(enabled bool) {
+	mouseLock.Lock()
+	defer mouseLock.Unlock()
+	mouseReporting = enabled
+	if enabled {
+		print(enableMouse)
+		updateMotionReporting(motionReporting)
+	} else {
+		motionReporting = false
+		print(disableMouse)
+	}
+}
+
+// updateMotionReporting asks the terminal to report the motions of the mouse without a button only while a painted node follows them,
+// so applications without hover do not receive a report for every motion. The caller holds mouseLock
+// @parma follow: whether a node follows the motions
+func updateMotionReporting(follow bool) {
+	follow = follow && mouseReporting
+	if follow == motionReporting {
+		return
+	}
+	motionReporting = follow
+	if follow {
+		print(enableMotion)
+	} else {
+		print(disableMotion)
+	}
+}
+
+// updateHover moves HoverNode and notifies the nodes that the mouse enters and leaves, ancestors of both nodes are not notified
+// @parma target: the node under the mouse
+func updateHover(target Node) {
+	if target == HoverNode {
+		return
+	}
+	oldPath := nodePath(HoverNode)
+	newPath := nodePath(target)
+	HoverNode = target
+	for _, node := range oldPath {
+		if !newPath.contains(node) && !node.isUnMount() {
+			triggerEvent(node, OnMouseLeave, target)
+		}
+	}
+	for _, node := range newPath {
+		if !oldPath.contains(node) {
+			triggerEvent(node, OnMouseEnter, target)
+		}
+	}
+}
+
+// nodePath returns a node and all its ancestors
+// @parma node: the first node of the path, may be nil
+func nodePath(node Node) NodeStack {
+	path := NodeStack{}
+	for ; node != nil; node, _ = node.GetParent() {
+		path = append(path, node)
+	}
+	return path
+}
+
+// contains reports whether the stack holds the node
+func (ns NodeStack) contains(node Node) bool {
+	for _, stackNode := range ns {
+		if stackNode == node {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardKey passes the last key of a widget to the closest ancestor that listens to keys, widgets call it with the keys they do not use
+// @parma node: the widget that received the key
+func forwardKey(node Node) {
+	event, _ := node.GetKeyBord()
+	for parent, _ := node.GetParent(); parent != nil; parent, _ = parent.GetParent() {
+		if hasEvent(parent, OnKeyBord) {
+			parent.setKeyBord(event)
+			triggerEvent(parent, OnKeyBord, node)
+			return
+		}
+	}
+}
+
+// hitTest finds the topmost visible node that covers a cell, nodes are visited in the order they are painted
+// @parma x: x-axis position y: y-axis position
+// @return the node, nil when the cell is outside Body
+func hitTest(x, y int) Node {
+	if Body == nil {
+		return nil
+	}
+	ensureLayout()
+
+	var hit Node = nil
+	var visit func(node Node)
+	visit = func(node Node) {
+		canvas := node.getCanvas()
+		if node.isUnMount() || !canvas.style.Display || (!canvas.displayed && canvas.childClip.empty()) {
+			return
+		}
+		if canvas.clip.contains(x, y) {
+			hit = node
+		}
+		zIndexTree := createZIndexTree(ZIndexRenderType)
+		zIndexTree.Init(canvas.children)
+		for child, ok := zIndexTree.GetNode(); ok; child, ok = zIndexTree.GetNode() {
+			if !child.getCanvas().position.Overlay {
+				visit(child)
+			}
+		}
+	}
+
+	visit(Body)
+	for _, layer := range frameLayers() { // Overlays are painted above the tree, the same way compositeLayers does
+		visit(layer)
+	}
+	return hit
+}
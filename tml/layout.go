@@ -0,0 +1,617 @@
+package tml
+
+import "reflect"
+
+// FlexContainer describes how a flex container places its children, see CanvasStyle.Layout
+type FlexContainer struct {
+	Direction uint8 //main axis of the container: FlexRow, FlexColumn, FlexRowReverse or FlexColumnReverse
+	Wrap      bool  //whether the items wrap onto new lines when they do not fit on the main axis
+	Justify   uint8 //how the free space of a line is distributed on the main axis, see JustifyStart
+	Align     uint8 //how the items are placed on the cross axis of their line, AlignAuto behaves like AlignStretch
+	Gap       int   //cells between two items and between two lines
+}
+
+// FlexItem describes how a node behaves as an item of a flex container, an empty FlexItem behaves like defaultFlexItem
+type FlexItem struct {
+	Grow      int   //weight used to share the free space of the line, 0 means the item does not grow
+	Shrink    int   //weight used to share the missing space of the line, 0 means the item does not shrink unless the whole FlexItem is empty
+	Basis     int   //initial size on the main axis, 0 uses the volume of the item
+	AlignSelf uint8 //overrides the Align of the container for this item, AlignAuto inherits it
+}
+
+// defaultFlexItem the properties of an item that leaves its FlexItem empty, it shrinks when the line is too small
+var defaultFlexItem = FlexItem{Shrink: 1}
+
+// GridTrack the size of a row or a column of a grid container
+type GridTrack struct {
+	Type uint8 //TrackFixed, TrackFraction or TrackAuto
+	Size int   //cells of a fixed track or weight of a fractional track, ignored by auto tracks
+}
+
+// GridContainer describes the tracks of a grid container, see CanvasStyle.Layout
+type GridContainer struct {
+	Columns   []GridTrack //column tracks from left to right, no column means a single fractional column
+	Rows      []GridTrack //row tracks from top to bottom, rows needed by the items beyond them are added as auto tracks
+	ColumnGap int         //cells between two columns
+	RowGap    int         //cells between two rows
+}
+
+// GridItem describes the cells a node occupies in a grid container
+type GridItem struct {
+	Column     int //first column of the item counted from 1, 0 lets the automatic placement choose the column
+	Row        int //first row of the item counted from 1, 0 lets the automatic placement choose the row
+	ColumnSpan int //number of columns covered by the item, 0 is treated as 1
+	RowSpan    int //number of rows covered by the item, 0 is treated as 1
+}
+
+// gridEntry an item of a grid container during the layout, positions are counted from 0
+type gridEntry struct {
+	node       Node
+	column     int
+	row        int
+	columnSpan int
+	rowSpan    int
+}
+
+// FixedTrack creates a track of a fixed number of cells
+func FixedTrack(size int) GridTrack {
+	return GridTrack{Type: TrackFixed, Size: size}
+}
+
+// FractionTrack creates a track that shares the free space of the container by weight
+func FractionTrack(weight int) GridTrack {
+	return GridTrack{Type: TrackFraction, Size: weight}
+}
+
+// AutoTrack creates a track as large as the largest item that only occupies it
+func AutoTrack() GridTrack {
+	return GridTrack{Type: TrackAuto}
+}
+
+// flexLine the items placed on the same line of a flex container
+type flexLine struct {
+	items     []flexEntry
+	crossSize int
+}
+
+// flexEntry an item of a flex container during the layout
+type flexEntry struct {
+	node      Node
+	item      FlexItem
+	mainSize  int
+	crossSize int
+	mainPos   int
+}
+
+// layoutTree the layout pass, computes and caches the rectangles of a node and all its children, using recursion.
+// Painting only reads the cached geometry, so the rectangles are known before and after a frame is drawn
+// @parma node: the root of the tree clip: the visible area left by all ancestors of the node
+func layoutTree(node Node, clip canvasRect) {
+	canvas := node.getCanvas()
+	canvas.displayed = false
+	canvas.childClip = canvasRect{}
+	if node.isUnMount() || !canvas.style.Display {
+		hideTree(node)
+		return
+	}
+
+	canvas.rect = confirmSquareBounds(canvas)
+	canvas.clip = canvas.rect.intersect(clip)
+	canvas.displayed = !canvas.clip.empty() // Nodes clipped out by their ancestors are still placed so their geometry can be queried
+
+	overflow := canvas.resolveOverflow()
+	canvas.childArea = canvas.contentRect()
+	viewport := canvas.childArea
+	canvas.scroll.enabled = scrolls(overflow.Horizontal) || scrolls(overflow.Vertical)
+	if canvas.scroll.enabled { // Children of a scrolling node are placed in the scrolled content and clipped to the viewport
+		viewport = layoutScroll(canvas)
+	}
+
+	layoutChildren(node) // Place the children that are managed by the layout of the node
+
+	canvas.childClip = viewport.intersect(canvas.clip) // Children are clipped to the intersection of the content areas of all ancestors
+	if overflow.Horizontal == OverflowVisible {        // Visible axes only keep the clip of the ancestors
+		canvas.childClip.left, canvas.childClip.right = clip.left, clip.right
+	}
+	if overflow.Vertical == OverflowVisible {
+		canvas.childClip.top, canvas.childClip.bottom = clip.top, clip.bottom
+	}
+	for _, child := range canvas.children {
+		if child.getCanvas().position.Overlay && canvas.displayed { // Overlays are only clipped by the screen
+			layoutTree(child, screenRect())
+		} else {
+			layoutTree(child, canvas.childClip)
+		}
+	}
+}
+
+// hideTree marks the children of a node that is not visible, their cached geometry is left as it was
+// @parma node: the hidden node
+func hideTree(node Node) {
+	for _, child := range node.getCanvas().children {
+		child.getCanvas().displayed = false
+		child.getCanvas().childClip = canvasRect{}
+		hideTree(child)
+	}
+}
+
+// ensureLayout runs the layout pass from Body when the cached geometry is outdated, the lock only serialises the layout passes,
+// a geometry read while a frame is painted is not guarded by it
+func ensureLayout() {
+	layoutLock.Lock()
+	defer layoutLock.Unlock()
+	if layoutDirty && Body != nil {
+		layoutTree(Body, screenRect())
+		layoutDirty = false
+	}
+}
+
+// invalidateLayout marks the cached geometry as outdated, it must be called whenever a property that affects geometry changes
+func invalidateLayout() {
+	layoutLock.Lock()
+	layoutDirty = true
+	layoutLock.Unlock()
+}
+
+// layoutStyleChanged reports whether a style change affects geometry, colors and text attributes do not
+// @parma oldStyle: the previous style style: the new style
+func layoutStyleChanged(oldStyle, style CanvasStyle) bool {
+	return oldStyle.Display != style.Display || oldStyle.AutoSize != style.AutoSize || oldStyle.BorderType != style.BorderType || oldStyle.Overflow != style.Overflow ||
+		oldStyle.Layout != style.Layout || oldStyle.Flex != style.Flex || oldStyle.FlexItem != style.FlexItem ||
+		oldStyle.GridItem != style.GridItem || !reflect.DeepEqual(oldStyle.Grid, style.Grid)
+}
+
+// canvasLayout converts the cached geometry of a node to its exported form
+func (c *Canvas) canvasLayout() CanvasLayout {
+	if !c.displayed {
+		return CanvasLayout{Bounds: c.rect.bounds(), Content: c.contentRect().bounds()}
+	}
+	return CanvasLayout{Bounds: c.rect.bounds(), Content: c.contentRect().bounds(), Visible: c.clip.bounds(), Display: true}
+}
+
+// layoutChildren computes the rectangles of the children of a node that manages their placement, it runs after the node is
+// placed and before its children are, so every child is placed inside the final content area of its parent
+// @parma node: the container whose children are placed
+func layoutChildren(node Node) {
+	canvas := node.getCanvas()
+	children, _ := node.GetChildren()
+
+	for _, child := range children { // Children fall back to their own position unless the layout places them
+		child.getCanvas().layoutManaged = false
+	}
+
+	switch canvas.style.Layout {
+	case LayoutFlex:
+		flexLayout(canvas.childArea, canvas.style.Flex, layoutItems(children))
+	case LayoutGrid:
+		gridLayout(canvas.childArea, canvas.style.Grid, layoutItems(children))
+	}
+}
+
+// layoutItems filters the children that take part in the layout of their parent, hidden nodes and overlays keep out of the flow
+// @parma children: the children of the container
+// @return the children placed by the layout
+func layoutItems(children []Node) NodeStack {
+	items := NodeStack{}
+	for _, child := range children {
+		canvas := child.getCanvas()
+		if child.isUnMount() || !canvas.style.Display || canvas.position.Overlay {
+			continue
+		}
+		items = append(items, child)
+	}
+	return items
+}
+
+// flexLayout places the items of a flex container
+// @parma content: content area of the container flex: properties of the container items: the items in order
+func flexLayout(content canvasRect, flex FlexContainer, items NodeStack) {
+	row := flex.Direction == FlexRow || flex.Direction == FlexRowReverse
+	reverse := flex.Direction == FlexRowReverse || flex.Direction == FlexColumnReverse
+	mainSize, crossSize := content.width(), content.height()
+	if !row {
+		mainSize, crossSize = crossSize, mainSize
+	}
+
+	lines := []flexLine{}
+	line := flexLine{}
+	lineSize := 0
+	for _, node := range items { // Split the items into lines
+		canvas := node.getCanvas()
+		entry := flexEntry{node: node, item: resolveFlexItem(canvas.style.FlexItem)}
+		entry.mainSize, entry.crossSize = flexBaseSize(canvas, content, row)
+		if mainUnit := flexMainUnit(canvas, row); mainUnit == SizeFill && entry.item.Grow == 0 { // Filling items take the free space of the line
+			entry.mainSize = 0
+			entry.item.Grow = 1
+		}
+
+		needed := entry.mainSize
+		if len(line.items) > 0 {
+			needed += flex.Gap
+		}
+		if flex.Wrap && len(line.items) > 0 && lineSize+needed > mainSize {
+			lines = append(lines, line)
+			line = flexLine{}
+			lineSize = 0
+			needed = entry.mainSize
+		}
+		line.items = append(line.items, entry)
+		lineSize += needed
+	}
+	if len(line.items) > 0 {
+		lines = append(lines, line)
+	}
+
+	for index := range lines { // Resolve the main axis and the cross size of every line
+		resolveFlexLine(&lines[index], mainSize, flex)
+		if len(lines) == 1 && !flex.Wrap {
+			lines[index].crossSize = crossSize
+		}
+		if reverse { // Reversed directions start from the end of the main axis
+			for itemIndex := range lines[index].items {
+				entry := &lines[index].items[itemIndex]
+				entry.mainPos = mainSize - entry.mainPos - entry.mainSize
+			}
+		}
+	}
+
+	crossPos := 0
+	for _, line := range lines {
+		for _, entry := range line.items {
+			align := entry.item.AlignSelf
+			if align == AlignAuto {
+				align = flex.Align
+			}
+			itemCross := entry.crossSize
+			offset := 0
+			if flexCrossUnit(entry.node.getCanvas(), row) == SizeFill {
+				align = AlignStretch
+			}
+			switch align {
+			case AlignAuto, AlignStretch:
+				itemCross = line.crossSize
+			case AlignCenter:
+				offset = (line.crossSize - itemCross) / 2
+			case AlignEnd:
+				offset = line.crossSize - itemCross
+			}
+
+			rect := canvasRect{}
+			if row {
+				rect.left = content.left + entry.mainPos
+				rect.top = content.top + crossPos + offset
+				rect.right = rect.left + entry.mainSize
+				rect.bottom = rect.top + itemCross
+			} else {
+				rect.top = content.top + entry.mainPos
+				rect.left = content.left + crossPos + offset
+				rect.bottom = rect.top + entry.mainSize
+				rect.right = rect.left + itemCross
+			}
+			placeLayoutItem(entry.node, rect)
+		}
+		crossPos += line.crossSize + flex.Gap
+	}
+}
+
+// resolveFlexItem returns the properties an item is placed with, a style that does not set them keeps the default ones
+// @parma item: the FlexItem of the style of the item
+func resolveFlexItem(item FlexItem) FlexItem {
+	if item == (FlexItem{}) {
+		return defaultFlexItem
+	}
+	return item
+}
+
+// flexBaseSize returns the size of an item before it grows or shrinks
+// @parma canvas: canvas of the item content: content area of the container row: whether the main axis is horizontal
+// @return size on the main axis, size on the cross axis
+func flexBaseSize(canvas *Canvas, content canvasRect, row bool) (int, int) {
+	mainSize, crossSize := resolveVolume(canvas, content, content.left, content.top)
+	if !row {
+		mainSize, crossSize = crossSize, mainSize
+	}
+	if canvas.style.FlexItem.Basis > 0 {
+		mainSize = canvas.style.FlexItem.Basis
+	}
+	return maxInt(mainSize, 0), maxInt(crossSize, 0)
+}
+
+// flexMainUnit returns the unit of the volume of an item on the main axis
+func flexMainUnit(canvas *Canvas, row bool) uint8 {
+	if row {
+		return canvas.volume.WidthUnit
+	}
+	return canvas.volume.HeightUnit
+}
+
+// flexCrossUnit returns the unit of the volume of an item on the cross axis
+func flexCrossUnit(canvas *Canvas, row bool) uint8 {
+	return flexMainUnit(canvas, !row)
+}
+
+// resolveFlexLine grows or shrinks the items of a line and places them on the main axis
+// @parma line: the line to resolve mainSize: size of the main axis of the container flex: properties of the container
+func resolveFlexLine(line *flexLine, mainSize int, flex FlexContainer) {
+	used := flex.Gap * (len(line.items) - 1)
+	totalGrow := 0
+	totalShrink := 0
+	for _, entry := range line.items {
+		used += entry.mainSize
+		totalGrow += entry.item.Grow
+		totalShrink += entry.item.Shrink * entry.mainSize
+		line.crossSize = maxInt(line.crossSize, entry.crossSize)
+	}
+
+	free := mainSize - used
+	if free > 0 && totalGrow > 0 { // Share the free space by the grow weight, the remainder goes to the first items
+		remain := free
+		for index := range line.items {
+			share := free * line.items[index].item.Grow / totalGrow
+			line.items[index].mainSize += share
+			remain -= share
+		}
+		for index := 0; remain > 0 && index < len(line.items); index++ {
+			if line.items[index].item.Grow > 0 {
+				line.items[index].mainSize++
+				remain--
+			}
+		}
+		free = 0
+	} else if free < 0 && totalShrink > 0 { // Share the missing space by the shrink weight scaled by the base size
+		missing := -free
+		remain := missing
+		for index := range line.items {
+			entry := &line.items[index]
+			share := minInt(entry.mainSize, missing*entry.item.Shrink*entry.mainSize/totalShrink)
+			entry.mainSize -= share
+			remain -= share
+		}
+		for index := 0; remain > 0 && index < len(line.items); index++ {
+			if entry := &line.items[index]; entry.item.Shrink > 0 && entry.mainSize > 0 {
+				entry.mainSize--
+				remain--
+			}
+		}
+		free = -remain
+	}
+
+	position, spacing := justifyOffsets(flex.Justify, maxInt(free, 0), len(line.items))
+	for index := range line.items {
+		line.items[index].mainPos = position
+		position += line.items[index].mainSize + flex.Gap + spacing
+	}
+}
+
+// justifyOffsets distributes the free space of a line
+// @parma justify: justify mode free: free space of the line count: number of items
+// @return position of the first item, extra space between two items
+func justifyOffsets(justify uint8, free int, count int) (int, int) {
+	if count == 0 {
+		return 0, 0
+	}
+	switch justify {
+	case JustifyEnd:
+		return free, 0
+	case JustifyCenter:
+		return free / 2, 0
+	case JustifySpaceBetween:
+		if count > 1 {
+			return 0, free / (count - 1)
+		}
+	case JustifySpaceAround:
+		spacing := free / count
+		return spacing / 2, spacing
+	case JustifySpaceEvenly:
+		spacing := free / (count + 1)
+		return spacing, spacing
+	}
+	return 0, 0
+}
+
+// gridLayout places the items of a grid container
+// @parma content: content area of the container grid: tracks of the container items: the items in order
+func gridLayout(content canvasRect, grid GridContainer, items NodeStack) {
+	columns := grid.Columns
+	if len(columns) == 0 {
+		columns = []GridTrack{FractionTrack(1)}
+	}
+	entries := placeGridItems(items, len(columns))
+
+	rows := append([]GridTrack{}, grid.Rows...)
+	for _, entry := range entries { // Add implicit rows for the items placed below the defined rows
+		for len(rows) < entry.row+entry.rowSpan {
+			rows = append(rows, AutoTrack())
+		}
+	}
+
+	columnSizes := resolveGridTracks(columns, content, grid.ColumnGap, entries, true)
+	rowSizes := resolveGridTracks(rows, content, grid.RowGap, entries, false)
+	columnStarts := trackStarts(columnSizes, grid.ColumnGap)
+	rowStarts := trackStarts(rowSizes, grid.RowGap)
+
+	for _, entry := range entries {
+		lastColumn := entry.column + entry.columnSpan - 1
+		lastRow := entry.row + entry.rowSpan - 1
+		placeLayoutItem(entry.node, canvasRect{
+			left:   content.left + columnStarts[entry.column],
+			top:    content.top + rowStarts[entry.row],
+			right:  content.left + columnStarts[lastColumn] + columnSizes[lastColumn],
+			bottom: content.top + rowStarts[lastRow] + rowSizes[lastRow],
+		})
+	}
+}
+
+// placeGridItems assigns cells to the items, items with an explicit position are placed first, then the items that only set their row
+// take the first free area of that row, and the others fill the free cells row by row in order, keeping the column they set
+// @parma items: the items in order columnCount: number of columns
+// @return the placed items
+func placeGridItems(items NodeStack, columnCount int) []gridEntry {
+	entries := make([]gridEntry, 0, len(items))
+	occupied := map[[2]int]bool{}
+	occupy := func(entry gridEntry) {
+		for row := entry.row; row < entry.row+entry.rowSpan; row++ {
+			for column := entry.column; column < entry.column+entry.columnSpan; column++ {
+				occupied[[2]int{row, column}] = true
+			}
+		}
+	}
+	free := func(entry gridEntry) bool {
+		for row := entry.row; row < entry.row+entry.rowSpan; row++ {
+			for column := entry.column; column < entry.column+entry.columnSpan; column++ {
+				if occupied[[2]int{row, column}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	rowItems := []gridEntry{}  // Items that only set their row
+	autoItems := []gridEntry{} // Items that only set their column or set neither
+	for _, node := range items {
+		gridItem := node.getCanvas().style.GridItem
+		entry := gridEntry{node: node, columnSpan: maxInt(gridItem.ColumnSpan, 1), rowSpan: maxInt(gridItem.RowSpan, 1)}
+		entry.columnSpan = minInt(entry.columnSpan, columnCount)
+		entry.column, entry.row = -1, -1
+		if gridItem.Column > 0 {
+			entry.column = minInt(gridItem.Column-1, columnCount-entry.columnSpan)
+		}
+		if gridItem.Row > 0 {
+			entry.row = gridItem.Row - 1
+		}
+		switch {
+		case entry.column >= 0 && entry.row >= 0:
+			occupy(entry)
+			entries = append(entries, entry)
+		case entry.row >= 0:
+			rowItems = append(rowItems, entry)
+		default:
+			autoItems = append(autoItems, entry)
+		}
+	}
+
+	for _, entry := range rowItems { // Search the first free area of the row, the first column is taken when the row is full
+		placed := false
+		for entry.column = 0; entry.column+entry.columnSpan <= columnCount; entry.column++ {
+			if free(entry) {
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			entry.column = 0
+		}
+		occupy(entry)
+		entries = append(entries, entry)
+	}
+
+	cursor := 0
+	for _, entry := range autoItems { // Search the next free area row by row
+		if entry.column >= 0 { // The column is fixed, only the row moves on
+			entry.row = cursor / columnCount
+			if entry.column < cursor%columnCount {
+				entry.row++
+			}
+			for !free(entry) {
+				entry.row++
+			}
+			cursor = entry.row*columnCount + entry.column
+		} else {
+			for {
+				entry.row = cursor / columnCount
+				entry.column = cursor % columnCount
+				if entry.column+entry.columnSpan <= columnCount && free(entry) {
+					break
+				}
+				cursor++
+			}
+		}
+		occupy(entry)
+		entries = append(entries, entry)
+		cursor += entry.columnSpan
+	}
+	return entries
+}
+
+// resolveGridTracks computes the size of every track of an axis
+// @parma tracks: track definitions content: content area of the container gap: cells between two tracks entries: placed items column: whether the axis is horizontal
+// @return the size of every track
+func resolveGridTracks(tracks []GridTrack, content canvasRect, gap int, entries []gridEntry, column bool) []int {
+	sizes := make([]int, len(tracks))
+	free := content.height() - gap*(len(tracks)-1)
+	if column {
+		free = content.width() - gap*(len(tracks)-1)
+	}
+	totalFraction := 0
+
+	for index, track := range tracks {
+		switch track.Type {
+		case TrackFixed:
+			sizes[index] = track.Size
+		case TrackAuto:
+			for _, entry := range entries { // The largest item that only occupies this track decides its size
+				start, span := entry.row, entry.rowSpan
+				width, size := resolveVolume(entry.node.getCanvas(), content, content.left, content.top)
+				if column {
+					start, span = entry.column, entry.columnSpan
+					size = width
+				}
+				if start == index && span == 1 {
+					sizes[index] = maxInt(sizes[index], size)
+				}
+			}
+		case TrackFraction:
+			totalFraction += maxInt(track.Size, 0)
+		}
+		free -= sizes[index]
+	}
+
+	if free > 0 && totalFraction > 0 { // Fractional tracks share the free space, the remainder goes to the first of them
+		remain := free
+		for index, track := range tracks {
+			if track.Type == TrackFraction {
+				sizes[index] = free * maxInt(track.Size, 0) / totalFraction
+				remain -= sizes[index]
+			}
+		}
+		for index := 0; remain > 0 && index < len(tracks); index++ {
+			if tracks[index].Type == TrackFraction && tracks[index].Size > 0 {
+				sizes[index]++
+				remain--
+			}
+		}
+	}
+	return sizes
+}
+
+// trackStarts returns the offset of every track from the start of the content area
+// @parma sizes: size of every track gap: cells between two tracks
+func trackStarts(sizes []int, gap int) []int {
+	starts := make([]int, len(sizes))
+	position := 0
+	for index, size := range sizes {
+		starts[index] = position
+		position += size + gap
+	}
+	return starts
+}
+
+// placeLayoutItem stores the rectangle computed by a layout, the node is drawn there instead of at its own position
+// @parma node: the placed node rect: absolute rectangle of the node
+func placeLayoutItem(node Node, rect canvasRect) {
+	canvas := node.getCanvas()
+	volume := canvas.volume
+	rect.right = rect.left + clampLength(rect.width(), volume.MinWidth, volume.MaxWidth) // The constraints of the item still apply
+	rect.bottom = rect.top + clampLength(rect.height(), volume.MinHeight, volume.MaxHeight)
+	canvas.layoutManaged = true
+	canvas.layoutRect = rect
+}
+
+// contentRect returns the area inside the border of the node computed by the last layout pass
+func (c *Canvas) contentRect() canvasRect {
+	if c.style.BorderType != None {
+		return c.rect.inset(1)
+	}
+	return c.rect
+}
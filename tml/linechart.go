@@ -0,0 +1,213 @@
+package tml
+
+import (
+	"errors"
+	"math"
+)
+
+// brailleDots the bits of the Braille dots of a cell, indexed by the column and the row of the dot
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// LineChart a chart of series drawn as lines of Braille dots, every cell holds two by four dots. The values of a series are spread
+// evenly over the width of the plot, the scale is on the left and the labels of the x axis below it
+type LineChart struct {
+	Quadrilateral               //inherited struct
+	series        []ChartSeries //the series
+	xLabels       []string      //labels spread evenly below the x axis
+	minValue      float64       //bottom of a fixed range
+	maxValue      float64       //top of a fixed range, the range is measured from the values when it is not larger than minValue
+	showLegend    bool          //whether the names of the series are shown above the chart
+}
+
+// CreateLineChart Creates an empty line chart that fills the remaining space of its parent
+// @parma name: the name of the node, does not force uniqueness
+// @return the line chart, loaded into each global repository before it returns
+func CreateLineChart(name string) *LineChart {
+	element := new(LineChart)
+	mountQuadrilateral(element, &element.Quadrilateral, LineChartTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFill, HeightUnit: SizeFill}
+	element.showLegend = true
+	return element
+}
+
+// SetSeries sets the series
+// @parma series: the series, a later series is drawn over the earlier ones
+func (lc *LineChart) SetSeries(series ...ChartSeries) error {
+	if lc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	lc.series = series
+	Render()
+	return nil
+}
+
+// GetSeries returns the series
+func (lc *LineChart) GetSeries() ([]ChartSeries, error) {
+	if lc.unMount {
+		return lc.series, errors.New(OperatingEmptyNodeError)
+	}
+	return lc.series, nil
+}
+
+// SetXLabels sets the labels below the x axis, labels that would touch the previous one are left out
+// @parma labels: the labels, spread evenly from the left to the right of the plot
+func (lc *LineChart) SetXLabels(labels ...string) error {
+	if lc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	lc.xLabels = labels
+	Render()
+	return nil
+}
+
+// SetRange fixes the values of the bottom and the top of the plot, values outside it are clamped
+// @parma min: value of the bottom max: value of the top, the range is measured from the values when it is not larger than min
+func (lc *LineChart) SetRange(min, max float64) error {
+	if lc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	lc.minValue, lc.maxValue = min, max
+	Render()
+	return nil
+}
+
+// SetShowLegend sets whether the names of the series are shown above the chart
+// @parma show: whether the legend is shown
+func (lc *LineChart) SetShowLegend(show bool) error {
+	if lc.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	lc.showLegend = show
+	Render()
+	return nil
+}
+
+// brailleCanvas the dots of a plot, a cell takes the color of the last series that draws in it
+type brailleCanvas struct {
+	width  int     //width in cells
+	height int     //height in cells
+	dots   []rune  //the dot bits of every cell
+	colors []Color //the color of every cell
+}
+
+// newBrailleCanvas creates an empty canvas
+// @parma width: width in cells height: height in cells
+func newBrailleCanvas(width, height int) *brailleCanvas {
+	return &brailleCanvas{width: width, height: height, dots: make([]rune, width*height), colors: make([]Color, width*height)}
+}
+
+// set turns a dot on, dots outside the canvas are ignored
+// @parma x: x-axis position in dots y: y-axis position in dots from the top color: color of the cell
+func (bc *brailleCanvas) set(x, y int, color Color) {
+	if x < 0 || y < 0 || x >= bc.width*2 || y >= bc.height*4 {
+		return
+	}
+	index := y/4*bc.width + x/2
+	bc.dots[index] |= brailleDots[x%2][y%4]
+	bc.colors[index] = color
+}
+
+// line draws a line of dots between two dots
+// @parma x0, y0: the first dot x1, y1: the last dot color: color of the cells
+func (bc *brailleCanvas) line(x0, y0, x1, y1 int, color Color) {
+	dx, dy := absInt(x1-x0), -absInt(y1-y0)
+	stepX, stepY := 1, 1
+	if x0 > x1 {
+		stepX = -1
+	}
+	if y0 > y1 {
+		stepY = -1
+	}
+	err := dx + dy
+	for {
+		bc.set(x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if double := 2 * err; double >= dy {
+			err += dy
+			x0 += stepX
+		} else {
+			err += dx
+			y0 += stepY
+		}
+	}
+}
+
+// lineChartRender paints the legend, the axes, the lines and the x labels of a line chart
+// @parma lc: pointer to the line chart struct
+// @return the render result of the node
+func lineChartRender(lc *LineChart) bool {
+	if lc.clip.empty() {
+		return false
+	}
+	style := lc.style
+	boxDrawing(&lc.Canvas, style)
+
+	content := lc.contentRect()
+	clip := content.intersect(lc.clip)
+	if hasLegend(lc.series, lc.showLegend) {
+		drawLegend(lc.series, content.left, content.top, style, clip)
+		content.top++
+	}
+	scale := autoScale(lc.series, false, lc.minValue, lc.maxValue)
+	_, labelWidth := chartValueLabels(scale)
+	plot := canvasRect{left: content.left + labelWidth + 1, top: content.top, right: content.right, bottom: content.bottom - 1}
+	if len(lc.xLabels) > 0 {
+		plot.bottom--
+	}
+	if plot.width() <= 0 || plot.height() <= 0 {
+		return true
+	}
+	drawAxes(plot, &scale, style, clip)
+
+	canvas := newBrailleCanvas(plot.width(), plot.height())
+	dotsWide, dotsHigh := plot.width()*2, plot.height()*4
+	for index, line := range lc.series {
+		color := seriesColor(line, index)
+		lastX, lastY, drawn := 0, 0, false
+		for position, value := range line.Values {
+			if math.IsNaN(value) { // A gap in the line
+				drawn = false
+				continue
+			}
+			x := 0
+			if len(line.Values) > 1 {
+				x = position * (dotsWide - 1) / (len(line.Values) - 1)
+			}
+			y := dotsHigh - 1 - int(math.Round(scale.fraction(value)*float64(dotsHigh-1)))
+			if drawn {
+				canvas.line(lastX, lastY, x, y, color)
+			} else {
+				canvas.set(x, y, color)
+			}
+			lastX, lastY, drawn = x, y, true
+		}
+	}
+	for row := 0; row < canvas.height; row++ {
+		for column := 0; column < canvas.width; column++ {
+			index := row*canvas.width + column
+			if canvas.dots[index] == 0 {
+				continue
+			}
+			dotStyle := style
+			dotStyle.Color = canvas.colors[index]
+			screenBuffer.set(plot.left+column, plot.top+row, styleCell(dotStyle, chartBrailleBlank+canvas.dots[index]), clip)
+		}
+	}
+
+	next := plot.left // The first cell a label may start at
+	for index, label := range lc.xLabels {
+		x := plot.left
+		if len(lc.xLabels) > 1 {
+			x += index * (plot.width() - 1) / (len(lc.xLabels) - 1)
+		}
+		runes := []rune(label)
+		x = minInt(maxInt(x-len(runes)/2, next), plot.right-len(runes)) // Labels are centered on their position and kept inside the plot
+		if x < next {
+			continue
+		}
+		next = drawChartText(label, x, plot.bottom+1, style, clip) + 1
+	}
+	return true
+}
@@ -0,0 +1,424 @@
+package tml
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ListSource the items of a List, the list only draws the items of the rows it shows so a source may hold any number of items.
+// Every item is read once after SetSource or Refresh when a type-ahead search or a width that fits the items needs it
+type ListSource interface {
+	Len() int                        //number of items
+	RenderItem(index int) StyledText //the text of an item, a row shows its first line
+}
+
+// StringList a ListSource made of plain strings
+type StringList []string
+
+// Len returns the number of strings
+func (sl StringList) Len() int {
+	return len(sl)
+}
+
+// RenderItem returns a string as plain text
+func (sl StringList) RenderItem(index int) StyledText {
+	return PlainText(sl[index])
+}
+
+// List a scrolling list of items with one row per item, only the visible rows are drawn. The cursor item is moved with the arrows,
+// PgUp, PgDn, Home, End and the mouse, typing jumps to the next item that starts with the typed text. In single selection the
+// selection follows the cursor, in multi selection Space and Ctrl+click toggle an item, Shift extends a range and Ctrl+A selects all.
+// OnSelectItem is triggered when the selection changes and OnActivate by Enter
+type List struct {
+	Quadrilateral              //inherited struct
+	source        ListSource   //the items
+	cursor        int          //index of the cursor item
+	anchor        int          //the item a Shift range starts from
+	selected      map[int]bool //the selected items
+	multiSelect   bool         //whether several items can be selected
+	typeAhead     string       //the text typed for the type-ahead search
+	typeAheadTime time.Time    //when the last rune of the type-ahead search was typed
+	indexed       bool         //whether itemIndex and itemWidth describe the current items
+	itemIndex     []string     //the lower-cased text of every item, the type-ahead search scans it instead of the source
+	itemWidth     int          //the width of the longest item
+}
+
+// CreateList Creates an empty list of 20 by 10 cells
+// @parma name: the name of the node, does not force uniqueness
+// @return the list, loaded into each global repository before it returns
+func CreateList(name string) *List {
+	element := new(List)
+	mountQuadrilateral(element, &element.Quadrilateral, ListTag, name)
+	element.volume = CanvasVolume{Width: 20, Height: 10}
+	element.overflow = CanvasOverflow{Horizontal: OverflowHidden, Vertical: OverflowAuto}
+	element.source = StringList{}
+	element.selected = map[int]bool{}
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		list := node.(*List)
+		if !list.navigateByKey() {
+			forwardKey(list)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*List).selectByMouse()
+	})
+
+	return element
+}
+
+// SetSource sets the items, the selection is cleared and the cursor is moved to the first item
+// @parma source: the items, nil empties the list
+func (l *List) SetSource(source ListSource) error {
+	if l.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if source == nil {
+		source = StringList{}
+	}
+	l.source = source
+	l.cursor, l.anchor = 0, 0
+	l.selected = map[int]bool{}
+	l.scroll.y = 0
+	if !l.multiSelect && source.Len() > 0 {
+		l.selected[0] = true
+	}
+	return l.Refresh()
+}
+
+// GetSource returns the items
+func (l *List) GetSource() (ListSource, error) {
+	if l.unMount {
+		return l.source, errors.New(OperatingEmptyNodeError)
+	}
+	return l.source, nil
+}
+
+// Refresh draws the list again after the items of the source changed, the cursor and the selection are kept inside the items
+func (l *List) Refresh() error {
+	if l.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	count := l.source.Len()
+	for index := range l.selected {
+		if index >= count {
+			delete(l.selected, index)
+		}
+	}
+	l.cursor = maxInt(minInt(l.cursor, count-1), 0)
+	l.indexed, l.itemIndex = false, nil
+	invalidateLayout()
+	Render()
+	return nil
+}
+
+// SetMultiSelect sets whether several items can be selected, leaving multi selection keeps the cursor item selected
+// @parma multi: whether several items can be selected
+func (l *List) SetMultiSelect(multi bool) error {
+	if l.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	l.multiSelect = multi
+	if !multi {
+		l.selectOnly(l.cursor)
+	}
+	Render()
+	return nil
+}
+
+// SetCursor moves the cursor to an item and scrolls it into view, in single selection the item is selected
+// @parma index: index of the item, it is clamped to the items
+func (l *List) SetCursor(index int) error {
+	if l.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	l.moveCursor(index, false)
+	return nil
+}
+
+// GetCursor returns the index of the cursor item, -1 when the list is empty
+func (l *List) GetCursor() (int, error) {
+	cursor := l.cursor
+	if l.source.Len() == 0 {
+		cursor = -1
+	}
+	if l.unMount {
+		return cursor, errors.New(OperatingEmptyNodeError)
+	}
+	return cursor, nil
+}
+
+// SetSelected replaces the selection without triggering OnSelectItem, only the first index is kept in single selection
+// @parma indexes: indexes of the selected items
+func (l *List) SetSelected(indexes ...int) error {
+	if l.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	l.selected = map[int]bool{}
+	for _, index := range indexes {
+		if index >= 0 && index < l.source.Len() {
+			l.selected[index] = true
+			if !l.multiSelect {
+				break
+			}
+		}
+	}
+	Render()
+	return nil
+}
+
+// GetSelected returns the indexes of the selected items in ascending order
+func (l *List) GetSelected() ([]int, error) {
+	indexes := make([]int, 0, len(l.selected))
+	for index := range l.selected {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	if l.unMount {
+		return indexes, errors.New(OperatingEmptyNodeError)
+	}
+	return indexes, nil
+}
+
+// selectOnly selects a single item
+func (l *List) selectOnly(index int) {
+	l.selected = map[int]bool{}
+	if index >= 0 && index < l.source.Len() {
+		l.selected[index] = true
+	}
+}
+
+// moveCursor moves the cursor, in single selection the cursor item is selected and a Shift range is selected in multi selection
+// @parma index: the new cursor, clamped to the items extend: whether the range from the anchor is selected
+func (l *List) moveCursor(index int, extend bool) {
+	count := l.source.Len()
+	if count == 0 {
+		return
+	}
+	index = maxInt(minInt(index, count-1), 0)
+	changed := false
+	switch {
+	case !l.multiSelect:
+		changed = !l.selected[index]
+		l.selectOnly(index)
+	case extend:
+		l.selected = map[int]bool{}
+		for item := minInt(l.anchor, index); item <= maxInt(l.anchor, index); item++ {
+			l.selected[item] = true
+		}
+		changed = true
+	}
+	if !extend {
+		l.anchor = index
+	}
+	l.cursor = index
+	l.scrollToCursor()
+	Render()
+	if changed {
+		triggerEvent(l, OnSelectItem, l)
+	}
+}
+
+// toggle selects or unselects an item in multi selection
+// @parma index: index of the item
+func (l *List) toggle(index int) {
+	if !l.multiSelect {
+		l.moveCursor(index, false)
+		return
+	}
+	if l.selected[index] {
+		delete(l.selected, index)
+	} else {
+		l.selected[index] = true
+	}
+	l.moveCursor(index, false)
+	triggerEvent(l, OnSelectItem, l)
+}
+
+// navigateByKey moves the cursor and the selection with the key of the last keyboard event
+// @return whether the key was used
+func (l *List) navigateByKey() bool {
+	extend := KeyModifier&ModShift != 0 && l.multiSelect
+	page := maxInt(l.scroll.viewport.height()-1, 1)
+	event := l.KeyEvent
+
+	switch event.Key {
+	case KeyArrowUp:
+		l.moveCursor(l.cursor-1, extend)
+	case KeyArrowDown:
+		l.moveCursor(l.cursor+1, extend)
+	case KeyPgup:
+		l.moveCursor(l.cursor-page, extend)
+	case KeyPgdn:
+		l.moveCursor(l.cursor+page, extend)
+	case KeyHome:
+		l.moveCursor(0, extend)
+	case KeyEnd:
+		l.moveCursor(l.source.Len()-1, extend)
+	case KeySpace:
+		if !l.multiSelect {
+			return l.searchByKey(' ')
+		}
+		l.toggle(l.cursor)
+	case KeyCtrlA:
+		if !l.multiSelect {
+			return false
+		}
+		l.anchor = 0
+		l.moveCursor(l.source.Len()-1, true)
+	case KeyEnter:
+		if l.source.Len() > 0 {
+			triggerEvent(l, OnActivate, l)
+		}
+	default:
+		if event.Key != 0 || event.Rune == 0 || !unicode.IsPrint(event.Rune) {
+			return false
+		}
+		return l.searchByKey(event.Rune)
+	}
+	return true
+}
+
+// searchByKey adds a rune to the type-ahead search and moves the cursor to the next item that starts with the typed text,
+// the search starts again after ListTypeAheadTime. Typing the same rune again cycles through the items that start with it
+// @parma char: the typed rune
+// @return whether the rune was used
+func (l *List) searchByKey(char rune) bool {
+	now := time.Now()
+	if now.Sub(l.typeAheadTime) > ListTypeAheadTime {
+		l.typeAhead = ""
+	}
+	l.typeAheadTime = now
+	l.typeAhead += string(char)
+
+	typed := []rune(strings.ToLower(l.typeAhead))
+	start := l.cursor
+	if strings.Count(string(typed), string(typed[0])) == len(typed) { // A new search or a repeated rune moves on to the next item
+		typed = typed[:1]
+		start++
+	}
+
+	items := l.indexItems()
+	count := len(items)
+	for offset := 0; offset < count; offset++ {
+		index := (start + offset) % count
+		if strings.HasPrefix(items[index], string(typed)) {
+			l.moveCursor(index, false)
+			return true
+		}
+	}
+	return count > 0
+}
+
+// selectByMouse selects the item under the left button, Ctrl toggles and Shift extends in multi selection
+func (l *List) selectByMouse() {
+	if l.mouse.Button != MouseLeft || l.mouse.Action != MousePress {
+		return
+	}
+	ensureLayout()
+	index := l.scroll.y + l.mouse.Y - l.scroll.viewport.top
+	if index < 0 || index >= l.source.Len() {
+		return
+	}
+	switch {
+	case l.mouse.Modifier&ModCtrl != 0:
+		l.toggle(index)
+	case l.mouse.Modifier&ModShift != 0:
+		l.moveCursor(index, l.multiSelect)
+	default:
+		if l.multiSelect {
+			l.selectOnly(index)
+			triggerEvent(l, OnSelectItem, l)
+		}
+		l.moveCursor(index, false)
+	}
+}
+
+// scrollToCursor scrolls the least distance that brings the cursor item into the viewport
+func (l *List) scrollToCursor() {
+	ensureLayout()
+	if !l.displayed {
+		return
+	}
+	height := l.scroll.viewport.height()
+	y := l.scroll.y
+	if l.cursor >= y+height {
+		y = l.cursor - height + 1
+	}
+	if l.cursor < y {
+		y = l.cursor
+	}
+	scrollNode(l, l.scroll.x, y)
+}
+
+// indexItems reads every item once after the source is set or refreshed, the layout passes and the type-ahead search use the result
+// @return the lower-cased text of every item
+func (l *List) indexItems() []string {
+	if l.indexed {
+		return l.itemIndex
+	}
+	count := l.source.Len()
+	l.itemIndex = make([]string, count)
+	l.itemWidth = 0
+	for index := 0; index < count; index++ {
+		item := l.source.RenderItem(index)
+		l.itemIndex[index] = strings.ToLower(item.String())
+		l.itemWidth = maxInt(l.itemWidth, item.Len())
+	}
+	l.indexed = true
+	return l.itemIndex
+}
+
+// measureContent measures the items of a list, the width is only measured when it is unknown and the items are read once per source
+// @parma width: the width of the viewport
+// @return the width of the longest item and the number of items
+func (l *List) measureContent(width int) (int, int) {
+	if width > 0 {
+		return width, l.source.Len()
+	}
+	count := len(l.indexItems())
+	return l.itemWidth, count
+}
+
+// listRender paints the rows of the visible items, selected items are reversed and the cursor item is underlined while the list is selected
+// @parma l: pointer to the list struct
+// @return the render result of the node
+func listRender(l *List) bool {
+	if l.clip.empty() {
+		return false
+	}
+	style := l.style
+	boxDrawing(&l.Canvas, style)
+	drawScrollBars(&l.Canvas, l.clip)
+
+	viewport := l.scroll.viewport
+	focused := SelectNode == Node(l)
+	count := l.source.Len()
+	for index := l.scroll.y; index < count && index < l.scroll.y+viewport.height(); index++ {
+		y := viewport.top + index - l.scroll.y
+		rowStyle := style
+		if l.selected[index] {
+			rowStyle.Attribute |= AttrReverse
+		}
+		if focused && index == l.cursor {
+			rowStyle.Attribute |= AttrUnderline
+		}
+		if rowStyle.Attribute != style.Attribute { // The whole row is highlighted
+			for x := viewport.left; x < viewport.right; x++ {
+				screenBuffer.set(x, y, styleCell(rowStyle, ' '), l.childClip)
+			}
+		}
+
+		item := l.source.RenderItem(index)
+		for column, itemRune := range item.runes() {
+			if itemRune.char == '\n' || column >= viewport.width() {
+				break
+			}
+			screenBuffer.set(viewport.left+column, y, spanCell(rowStyle, item[itemRune.span], itemRune.char), l.childClip)
+		}
+	}
+	return true
+}
@@ -2,7 +2,6 @@ package tml
 
 import (
 	"fmt"
-	"github.com/eiannone/keyboard"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/ssh/terminal"
 	"os"
@@ -31,52 +30,79 @@ func listenWindowSize(fy int) {
 		if (width != SysWidth || height != SysHeight) && Body != nil {
 			SysWidth = width
 			SysHeight = height
-			Render()
+			invalidateLayout()
+			Render() // The layouts of all containers are recomputed for the new size
 			autoSizeChangeTrigger(Body, Body, true)
-
-			time.Sleep(time.Millisecond * time.Duration(fy))
 		}
 
+		time.Sleep(time.Millisecond * time.Duration(fy))
 	}
 }
 
-// listenKeyBord Continuously listens for global keyboard events
+// listenKeyBord Continuously listens for global keyboard and mouse events, the terminal is switched to raw mode while listening
 func listenKeyBord() {
-	err := keyboard.Open()
+	fd := int(os.Stdin.Fd())
+	oldState, err := terminal.MakeRaw(fd)
 	if err != nil {
 		panic(err)
 	}
-	defer keyboard.Close()
-	for {
-		char, key, err := keyboard.GetKey()
+	defer terminal.Restore(fd, oldState)
 
-		if err != nil {
-			panic(err.Error())
+	if MouseEnabled {
+		setMouseReporting(true)
+		defer setMouseReporting(false)
+	}
+
+	reads := make(chan []byte)
+	go readInput(reads)
+
+	reader := inputReader{}
+	for {
+		var events []inputEvent
+		if len(reader.pending) > 0 { // A sequence split between two reads waits a little for the rest
+			select {
+			case data := <-reads:
+				events = reader.feed(data)
+			case <-time.After(EscapeDelay):
+				events = reader.flush()
+			}
+		} else {
+			events = reader.feed(<-reads)
 		}
 
-		if SelectNode == nil {
-			SelectNode = Body
+		for _, event := range events {
+			if event.mouse != nil {
+				dispatchMouse(*event.mouse)
+			} else {
+				dispatchKey(event.key, event.modifier)
+			}
 		}
+	}
+}
 
-		SelectNode.setKeyBord(keyboard.KeyEvent{
-			Key:  key,
-			Rune: char,
-			Err:  err,
-		})
+// readInput Continuously reads the terminal input and sends every read to the channel
+// @parma reads: receives a copy of the bytes of every read
+func readInput(reads chan<- []byte) {
+	data := make([]byte, 256)
+	for {
+		size, err := os.Stdin.Read(data)
 
-		if SelectNode.isUnMount() || !triggerEvent(SelectNode, OnKeyBord, SelectNode) { //尝试检测节点是否失效，如果失效会进行回退
-			backSelect(SelectNode)
+		if err != nil {
+			panic(err.Error())
 		}
+
+		reads <- append([]byte(nil), data[:size]...)
 	}
 }
 
 // Select the select a node to be used as the output node to listen for onKeyBord events. This node must listen for OnKeyBord events. Otherwise, the node automatically rolls back until the parent node has a listener or Body
 // @parma node Selected node
 func Select(node Node) {
-	if node != nil {
+	if node != nil && modalAllowsSelect(node) { // Nodes beneath a modal cannot be selected
 		oldSelectNode := SelectNode
 		SelectNode = node
 		triggerEvent(node, OnSelect, oldSelectNode)
+		Render() // Widgets are drawn differently when they are selected
 	}
 }
 
@@ -105,6 +131,11 @@ func Start(fy uint16, keyBordEvent bool) {
 		return
 	}
 
+	// Detect the colors that the terminal is able to display
+	if ColorMode == AutoColorProfile {
+		ColorMode = DetectColorProfile()
+	}
+
 	// Example Initialize the name class map
 	nameLibrary = make(map[string]NodeStack)
 
@@ -136,16 +167,15 @@ func Start(fy uint16, keyBordEvent bool) {
 	isInit = true
 }
 
-// elementLoop renders a node and all its children, using recursion
-// @parma The node tree that will be rendered
+// elementLoop renders a node and all its children, using recursion. The geometry of the nodes comes from the layout pass
+// @parma node: The node tree that will be rendered
 func elementLoop(node Node) {
 
-	if node == Body {
-		globalBuf.Reset() // Initialize the output file when re-rendering
-	}
-
 	renderResult := renderer(node) // Render node first to determine the node adaptability
-	if !renderResult {             // If the parent component cannot render, the rendering of all child components is stopped
+	if renderResult && !frameFollowsMotion && followsMotion(node) {
+		frameFollowsMotion = true
+	}
+	if !renderResult && node.getCanvas().childClip.empty() { // If the parent component cannot render, the rendering of all child components is stopped unless they overflow visibly
 		return
 	}
 
@@ -154,11 +184,53 @@ func elementLoop(node Node) {
 	zIndexTree.Init(child)
 	childNode, ok := zIndexTree.GetNode()
 	for ok {
-		elementLoop(childNode)
+		if childPosition, _ := childNode.GetPosition(); !childPosition.Overlay { // Overlays are lifted out of the tree and painted by the compositor
+			elementLoop(childNode)
+		}
 		childNode, ok = zIndexTree.GetNode()
 	}
 }
 
+// collectLayers gathers the overlays of a tree in painting order, an overlay nested in an overlay follows it
+// @parma node: The root of the tree  layers: The overlays found so far
+func collectLayers(node Node, layers *NodeStack) {
+	canvas := node.getCanvas()
+	if node.isUnMount() || !canvas.style.Display || (!canvas.displayed && canvas.childClip.empty()) { // The same nodes elementLoop reaches
+		return
+	}
+	zIndexTree := createZIndexTree(ZIndexRenderType)
+	zIndexTree.Init(canvas.children)
+	for child, ok := zIndexTree.GetNode(); ok; child, ok = zIndexTree.GetNode() {
+		if child.getCanvas().position.Overlay {
+			*layers = append(*layers, child)
+		}
+		collectLayers(child, layers)
+	}
+}
+
+// frameLayers returns every overlay of the tree of Body sorted by ZIndex, overlays of the same ZIndex keep their painting order
+// so an overlay nested in another one with the same ZIndex is above it
+func frameLayers() NodeStack {
+	layers := NodeStack{}
+	collectLayers(Body, &layers)
+
+	sorted := NodeStack{}
+	layerTree := createZIndexTree(ZIndexRenderType)
+	layerTree.Init(layers)
+	for layer, ok := layerTree.GetNode(); ok; layer, ok = layerTree.GetNode() {
+		sorted = append(sorted, layer)
+	}
+	return sorted
+}
+
+// compositeLayers paints the overlays of the frame, every overlay is a stacking context that is painted above the normal tree
+// and is only clipped by the screen. All overlays of the frame are ordered by ZIndex together, wherever they are nested
+func compositeLayers() {
+	for _, layer := range frameLayers() {
+		elementLoop(layer)
+	}
+}
+
 // notRenderable is mainly used to detect whether the screen can be rendered
 // @return The terminal cannot be used for rendering when the return value is true
 func notRenderable() bool {
@@ -178,9 +250,26 @@ func startElementLoop(fy int) {
 // renderDebounce Render anti-shake function, synchronization code after asynchronous rendering
 var renderDebounce = debounce(RenderLazy)
 
+// renderLock keeps renders from overlapping, the animations are advanced by one render at a time
+var renderLock sync.Mutex
+
 // render render function
 func render() {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	globalBuf.Reset() // Initialize the output file when re-rendering
+	advanceAnimations(time.Now())
+	ensureLayout() // Geometry is only recomputed when it has been invalidated
+	screenBuffer.reset(SysWidth, SysHeight)
+	frameFollowsMotion = false
 	elementLoop(Body)
+	compositeLayers()
+
+	mouseLock.Lock()
+	updateMotionReporting(frameFollowsMotion)
+	mouseLock.Unlock()
+
+	screenBuffer.output(&globalBuf)
 	print(globalBuf.String())
 }
 
@@ -219,9 +308,8 @@ func renderer(node Node) bool {
 	}
 	nodeAttr, _ := node.GetAttr()
 	nodeStyle, _ := node.GetStyle()
-	nodeVolume, _ := node.GetVolume()
 
-	if nodeStyle.Display == false || (nodeStyle.AutoSize == false && (nodeVolume.Width <= 0 || nodeVolume.Height <= 0)) {
+	if nodeStyle.Display == false || !node.getCanvas().displayed { // Nodes that the layout pass found invisible are skipped
 		return false
 	}
 
@@ -229,6 +317,40 @@ func renderer(node Node) bool {
 
 	case QuadrilateralTag:
 		return quadrilateralRender(node.(*Quadrilateral))
+	case ScrollViewTag:
+		return quadrilateralRender(&node.(*ScrollView).Quadrilateral)
+	case TextTag:
+		return textRender(node.(*Text))
+	case ButtonTag:
+		return buttonRender(node.(*Button))
+	case InputTag:
+		return inputRender(node.(*Input))
+	case TextAreaTag:
+		return textAreaRender(node.(*TextArea))
+	case ListTag:
+		return listRender(node.(*List))
+	case TableTag:
+		return tableRender(node.(*Table))
+	case TreeTag:
+		return treeRender(node.(*Tree))
+	case TabsTag:
+		return tabsRender(node.(*Tabs))
+	case ModalTag:
+		return modalRender(node.(*Modal))
+	case MenuBarTag:
+		return menuBarRender(node.(*MenuBar))
+	case MenuTag:
+		return menuRender(node.(*menuPopup))
+	case ProgressBarTag:
+		return progressBarRender(node.(*ProgressBar))
+	case SpinnerTag:
+		return spinnerRender(node.(*Spinner))
+	case SparklineTag:
+		return sparklineRender(node.(*Sparkline))
+	case BarChartTag:
+		return barChartRender(node.(*BarChart))
+	case LineChartTag:
+		return lineChartRender(node.(*LineChart))
 	}
 	return false
 }
@@ -237,18 +359,9 @@ func renderer(node Node) bool {
 // @parma ql: pointer to the quadrilateral struct
 // @return returns the render result of the node, false if it does not render properly, so his children will not parse the render again
 func quadrilateralRender(ql *Quadrilateral) bool {
-
-	if (ql.volume.Width <= 0 || ql.volume.Height <= 0) && !ql.style.AutoSize {
+	if ql.clip.empty() {
 		return false
 	} else {
-		ql.position.totalY = ql.position.Y
-		ql.position.totalX = ql.position.X
-		if ql.parent != nil {
-			pPosition, _ := ql.parent.GetPosition()
-			ql.position.totalY += pPosition.totalY
-			ql.position.totalX += pPosition.totalX
-		}
-
 		return squareDrawing(ql)
 	}
 }
@@ -257,9 +370,17 @@ func quadrilateralRender(ql *Quadrilateral) bool {
 // @parma Name: Creates the name of the quadrilateral to mark the node, but does not force uniqueness
 // @return creates a node that will be loaded into each global repository before it returns
 func CreateQuadrilateral(name string) Node {
-	key, _ := uuid.NewRandom()
 	element := new(Quadrilateral)
-	element.tag = QuadrilateralTag
+	mountQuadrilateral(element, element, QuadrilateralTag, name)
+	return element
+}
+
+// mountQuadrilateral initializes the Quadrilateral of a node and loads the node into each global repository, widgets that embed a Quadrilateral call it from their constructor
+// @parma node: the node that embeds the quadrilateral element: the embedded quadrilateral tag: type of the node name: name of the node
+func mountQuadrilateral(node Node, element *Quadrilateral, tag string, name string) {
+	key, _ := uuid.NewRandom()
+	element.self = node
+	element.tag = tag
 	element.name = name
 	element.key = fmt.Sprintf("%s", key)
 	element.position.ZIndex = 0
@@ -269,12 +390,10 @@ func CreateQuadrilateral(name string) Node {
 
 	element.style = DefaultStyle
 
-	loadNodeToBase(element)        // Loads the node into the sort repository
-	lodeNodeToNameIndex(element)   // Loads a node into a repository classified by the Name field
-	loadNodeToRenderStack(element) // Loads a node into the render-level repository
-	createEvent(element)           // Create an event
-
-	return element
+	loadNodeToBase(node)        // Loads the node into the sort repository
+	lodeNodeToNameIndex(node)   // Loads a node into a repository classified by the Name field
+	loadNodeToRenderStack(node) // Loads a node into the render-level repository
+	createEvent(node)           // Create an event
 }
 
 // CreateNode Easy to create different types of nodes
@@ -285,6 +404,38 @@ func CreateNode(nodeType string, name string) Node {
 	switch nodeType {
 	case QuadrilateralTag:
 		node = CreateQuadrilateral(name)
+	case ScrollViewTag:
+		node = CreateScrollView(name)
+	case TextTag:
+		node = CreateText(name)
+	case ButtonTag:
+		node = CreateButton(name, "")
+	case InputTag:
+		node = CreateInput(name)
+	case TextAreaTag:
+		node = CreateTextArea(name)
+	case ListTag:
+		node = CreateList(name)
+	case TableTag:
+		node = CreateTable(name)
+	case TreeTag:
+		node = CreateTree(name)
+	case TreeItemTag:
+		node = CreateTreeItem(name, "")
+	case TabsTag:
+		node = CreateTabs(name)
+	case MenuBarTag:
+		node = CreateMenuBar(name)
+	case ProgressBarTag:
+		node = CreateProgressBar(name, "")
+	case SpinnerTag:
+		node = CreateSpinner(name, "")
+	case SparklineTag:
+		node = CreateSparkline(name)
+	case BarChartTag:
+		node = CreateBarChart(name)
+	case LineChartTag:
+		node = CreateLineChart(name)
 	}
 	return node
 }
@@ -300,6 +451,8 @@ func loadNodeToBase(node Node) {
 			quadrilateralStore.Store(ql.key, ql) // Stored in a global variable to facilitate the renderer to obtain information
 		}
 		break
+	case ScrollViewTag, TextTag, ButtonTag, InputTag, TextAreaTag, ListTag, TableTag, TreeTag, TreeItemTag, TabsTag, ModalTag, MenuBarTag, MenuTag, ProgressBarTag, SpinnerTag, SparklineTag, BarChartTag, LineChartTag: // Widgets built on a Quadrilateral share one repository
+		widgetStore.Store(nodeAttr.Key, node)
 	}
 }
 
@@ -354,7 +507,12 @@ func getElementFromBase(key string) (Node, bool) {
 		return node, ok
 	}
 
-	return node, ok
+	widget, ok := widgetStore.Load(key)
+	if ok {
+		return widget.(Node), ok
+	}
+
+	return nil, ok
 }
 
 // delNodeFromBase Deletes the node from the corresponding global repository
@@ -368,6 +526,8 @@ func delNodeFromBase(node Node) {
 			quadrilateralStore.Delete(ql.key) //Save to global variables to facilitate the renderer to obtain information
 		}
 		break
+	case ScrollViewTag, TextTag, ButtonTag, InputTag, TextAreaTag, ListTag, TableTag, TreeTag, TreeItemTag, TabsTag, ModalTag, MenuBarTag, MenuTag, ProgressBarTag, SpinnerTag, SparklineTag, BarChartTag, LineChartTag:
+		widgetStore.Delete(nodeAttr.Key)
 	}
 }
 
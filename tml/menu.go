@@ -0,0 +1,761 @@
+package tml
+
+import (
+	"errors"
+	"unicode"
+
+	"github.com/eiannone/keyboard"
+)
+
+// Menu characters
+const (
+	menuCheckRune     = '✓'
+	menuSubmenuRune   = '▶'
+	menuSeparatorRune = '-'
+)
+
+// menuBars the mounted menu bars, their accelerators are checked before a key is delivered
+var menuBars []*MenuBar
+
+// Accelerator a key combination that activates a menu item of a menu bar from anywhere while no modal is shown
+type Accelerator struct {
+	Key      keyboard.Key //the key, Alt combinations are reported as KeyEsc with a Rune
+	Rune     rune         //the rune of the key, 0 for functional keys
+	Modifier uint8        //modifier keys held with the key, see ModShift
+	Text     string       //how the combination is shown in the menu, such as Ctrl+S
+}
+
+// MenuItem an item of a menu, an item with Items opens a submenu
+type MenuItem struct {
+	Label       string          //text of the item, the rune after & is its mnemonic and && is a literal &
+	Accelerator Accelerator     //activates the item from anywhere, the zero value is no accelerator
+	Separator   bool            //the item is a line between two groups of items, the other fields are ignored
+	Checkable   bool            //activating the item toggles Checked
+	Checked     bool            //whether a check mark is shown before the label
+	Disabled    bool            //the item is dimmed and cannot be activated
+	Items       []*MenuItem     //the items of the submenu
+	Action      func(*MenuItem) //called with the item when it is activated, after the menus are closed
+}
+
+// selectable reports whether the cursor can stop on the item
+func (mi *MenuItem) selectable() bool {
+	return !mi.Separator && !mi.Disabled
+}
+
+// mnemonic returns the lower case mnemonic of the item, 0 when it has none
+func (mi *MenuItem) mnemonic() rune {
+	label, index := mnemonicLabel(mi.Label)
+	if index < 0 {
+		return 0
+	}
+	return unicode.ToLower(label[index])
+}
+
+// matches reports whether a key is the accelerator
+// @parma event: the key modifier: the modifier keys held with the key
+func (a Accelerator) matches(event keyboard.KeyEvent, modifier uint8) bool {
+	return (a.Key != 0 || a.Rune != 0) && a.Key == event.Key && a.Rune == event.Rune && a.Modifier == modifier
+}
+
+// mnemonicLabel removes the & markers of a label
+// @parma label: the label of an item
+// @return the runes of the label and the index of the mnemonic, -1 when there is none
+func mnemonicLabel(label string) ([]rune, int) {
+	source := []rune(label)
+	runes := make([]rune, 0, len(source))
+	mnemonic := -1
+	for index := 0; index < len(source); index++ {
+		if source[index] == '&' && index+1 < len(source) {
+			index++
+			if source[index] != '&' && mnemonic < 0 {
+				mnemonic = len(runes)
+			}
+		}
+		runes = append(runes, source[index])
+	}
+	return runes, mnemonic
+}
+
+// findMnemonic returns the index of the first selectable item whose mnemonic is a rune, -1 when there is none
+// @parma items: the items char: the typed rune
+func findMnemonic(items []*MenuItem, char rune) int {
+	char = unicode.ToLower(char)
+	for index, item := range items {
+		if item.selectable() && item.mnemonic() == char {
+			return index
+		}
+	}
+	return -1
+}
+
+// findAccelerator searches the items and their submenus for the selectable item of an accelerator
+// @parma items: the items event: the key modifier: the modifier keys held with the key
+// @return the item, nil when no item uses the key
+func findAccelerator(items []*MenuItem, event keyboard.KeyEvent, modifier uint8) *MenuItem {
+	for _, item := range items {
+		if !item.selectable() {
+			continue
+		}
+		if len(item.Items) == 0 && item.Accelerator.matches(event, modifier) {
+			return item
+		}
+		if found := findAccelerator(item.Items, event, modifier); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// nextSelectable returns the next selectable item in a direction, the search wraps around
+// @parma items: the items from: the index the search starts after step: 1 to search down, -1 to search up
+// @return the index of the item, -1 when no item is selectable
+func nextSelectable(items []*MenuItem, from int, step int) int {
+	count := len(items)
+	for offset := 1; offset <= count; offset++ {
+		index := ((from+step*offset)%count + count) % count
+		if items[index].selectable() {
+			return index
+		}
+	}
+	return -1
+}
+
+// runItem toggles a checkable item and calls its action
+func runItem(item *MenuItem) {
+	if item.Checkable {
+		item.Checked = !item.Checked
+	}
+	Render()
+	if item.Action != nil {
+		item.Action(item)
+	}
+}
+
+// menuShortcut gives the keys of the menu bars a chance before the selected node, F10 opens the first menu, Alt and a mnemonic
+// opens a menu and accelerators activate their items
+// @parma event: the key modifier: the modifier keys held with the key
+// @return whether a menu bar used the key
+func menuShortcut(event keyboard.KeyEvent, modifier uint8) bool {
+	if len(menuBars) == 0 {
+		return false
+	}
+	ensureLayout() // Hidden menu bars do not use keys
+	for _, bar := range menuBars {
+		if bar.unMount || !bar.displayed || len(bar.menus) == 0 {
+			continue
+		}
+		switch {
+		case event.Key == KeyF10 && modifier == 0:
+			bar.openMenu(maxInt(nextSelectable(bar.menus, -1, 1), 0))
+			return true
+		case event.Key == KeyEsc && event.Rune != 0:
+			if index := findMnemonic(bar.menus, event.Rune); index >= 0 {
+				bar.openMenu(index)
+				return true
+			}
+		}
+		if item := findAccelerator(bar.menus, event, modifier); item != nil {
+			runItem(item)
+			return true
+		}
+	}
+	return false
+}
+
+// menuSession the menus opened from a menu bar or a context menu. They are shown in a modal that does not dim, so the nodes beneath
+// cannot be reached and a click outside the menus closes them
+type menuSession struct {
+	layer  *Modal       //the modal that holds the menus
+	popups []*menuPopup //the open menus from the first one to the deepest submenu
+	bar    *MenuBar     //the menu bar the menus belong to, nil for a context menu
+}
+
+// openMenuSession shows a menu with its top left corner at a cell, it is moved to stay on the screen
+// @parma items: the items of the menu x: x-axis position y: y-axis position bar: the menu bar it belongs to, may be nil onClose: called after the menus are closed
+func openMenuSession(items []*MenuItem, x, y int, bar *MenuBar, onClose func()) *menuSession {
+	session := &menuSession{bar: bar}
+	popup := session.createPopup(items, 0)
+	placePopup(popup, x, y)
+	session.popups = []*menuPopup{popup}
+	session.layer = showOverlay(popup, false, onClose)
+	session.layer.AddEventListener(OnMouse, func(node Node, origen Node) {
+		session.clickOutside()
+	})
+	return session
+}
+
+// createPopup creates a menu that is as large as its items
+// @parma items: the items depth: 0 for the first menu, the depth of a submenu is one more than the menu that opened it
+func (s *menuSession) createPopup(items []*MenuItem, depth int) *menuPopup {
+	element := new(menuPopup)
+	mountQuadrilateral(element, &element.Quadrilateral, MenuTag, "menu")
+	element.session = s
+	element.items = items
+	element.depth = depth
+	element.cursor = nextSelectable(items, -1, 1)
+	element.style.BorderType = ContinuousLine
+	element.volume = CanvasVolume{Width: menuWidth(items), Height: len(items) + 2}
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		node.(*menuPopup).handleKey()
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*menuPopup).handleMouse()
+	})
+	return element
+}
+
+// followsMotion reports that a menu follows the mouse without a button, the item under the mouse is highlighted
+func (p *menuPopup) followsMotion() bool {
+	return true
+}
+
+// placePopup places a menu at a cell, it is moved left and up when it would leave the screen
+// @parma popup: the menu x: x-axis position y: y-axis position
+func placePopup(popup *menuPopup, x, y int) {
+	x = maxInt(minInt(x, SysWidth-popup.volume.Width), 0)
+	y = maxInt(minInt(y, SysHeight-popup.volume.Height), 0)
+	popup.SetPosition(CanvasPosition{X: x, Y: y, ZIndex: uint32(popup.depth)})
+}
+
+// close closes all the menus of the session, the selection goes back to the node selected before they opened
+func (s *menuSession) close() {
+	if s.layer == nil {
+		return
+	}
+	for index := len(s.popups) - 1; index > 0; index-- {
+		s.popups[index].Remove()
+	}
+	s.popups = nil
+	layer := s.layer
+	s.layer = nil
+	layer.Close()
+}
+
+// closeFrom closes the submenus from a depth on and selects the menu that opened them
+// @parma depth: depth of the first submenu to close, at least 1
+func (s *menuSession) closeFrom(depth int) {
+	if depth <= 0 || depth >= len(s.popups) {
+		return
+	}
+	for index := len(s.popups) - 1; index >= depth; index-- {
+		s.popups[index].Remove()
+	}
+	s.popups = s.popups[:depth]
+	Select(s.popups[depth-1])
+}
+
+// openSubmenu opens the submenu of the cursor item of a menu on its right, or on its left when there is no room on the right
+// @parma popup: the menu focus: whether the submenu is selected
+func (s *menuSession) openSubmenu(popup *menuPopup, focus bool) {
+	if popup.cursor < 0 {
+		return
+	}
+	item := popup.items[popup.cursor]
+	if !item.selectable() || len(item.Items) == 0 {
+		return
+	}
+	s.closeFrom(popup.depth + 1)
+	ensureLayout()
+	submenu := s.createPopup(item.Items, popup.depth+1)
+	x := popup.rect.right
+	if x+submenu.volume.Width > SysWidth {
+		x = popup.rect.left - submenu.volume.Width
+	}
+	placePopup(submenu, x, popup.rect.top+popup.cursor)
+	s.layer.Insert(submenu)
+	s.popups = append(s.popups, submenu)
+	if focus {
+		Select(submenu)
+	}
+	Render()
+}
+
+// activate activates an item of a menu, an item with a submenu opens it and the other items close all the menus and run
+// @parma popup: the menu index: index of the item
+func (s *menuSession) activate(popup *menuPopup, index int) {
+	item := popup.items[index]
+	if !item.selectable() {
+		return
+	}
+	popup.cursor = index
+	if len(item.Items) > 0 {
+		s.openSubmenu(popup, true)
+		return
+	}
+	s.close()
+	runItem(item)
+}
+
+// clickOutside closes the menus when a button is pressed outside them, a press on a title of the menu bar opens that menu instead
+func (s *menuSession) clickOutside() {
+	if s.layer == nil {
+		return
+	}
+	mouse, _ := s.layer.GetMouse()
+	if mouse.Action != MousePress {
+		return
+	}
+	if s.bar != nil {
+		if index := s.bar.menuAt(mouse.X, mouse.Y); index >= 0 && index != s.bar.openIndex {
+			s.bar.openMenu(index)
+			return
+		}
+	}
+	s.close()
+}
+
+// menuPopup a menu opened from a menu bar, a context menu or another menu
+type menuPopup struct {
+	Quadrilateral              //inherited struct
+	session       *menuSession //the session the menu belongs to
+	items         []*MenuItem  //the items
+	depth         int          //0 for the first menu, one more for every submenu
+	cursor        int          //index of the cursor item, -1 when no item is selectable
+}
+
+// handleKey moves the cursor with the arrows, Home and End, opens and closes submenus with Right and Left, activates the cursor item
+// with Enter or Space and an item with its mnemonic. Esc closes the menu
+func (p *menuPopup) handleKey() {
+	s := p.session
+	event := p.KeyEvent
+	switch event.Key {
+	case KeyArrowUp:
+		p.moveCursor(nextSelectable(p.items, p.cursor, -1))
+	case KeyArrowDown:
+		p.moveCursor(nextSelectable(p.items, p.cursor, 1))
+	case KeyHome:
+		p.moveCursor(nextSelectable(p.items, -1, 1))
+	case KeyEnd:
+		p.moveCursor(nextSelectable(p.items, len(p.items), -1))
+	case KeyArrowRight:
+		if p.cursor >= 0 && len(p.items[p.cursor].Items) > 0 {
+			s.openSubmenu(p, true)
+		} else if s.bar != nil {
+			s.bar.switchMenu(1)
+		}
+	case KeyArrowLeft:
+		if p.depth > 0 {
+			s.closeFrom(p.depth)
+		} else if s.bar != nil {
+			s.bar.switchMenu(-1)
+		}
+	case KeyEnter, KeySpace:
+		if p.cursor >= 0 {
+			s.activate(p, p.cursor)
+		}
+	case KeyEsc:
+		switch {
+		case event.Rune != 0: // Alt and a mnemonic of the menu bar moves to that menu
+			if s.bar != nil {
+				if index := findMnemonic(s.bar.menus, event.Rune); index >= 0 {
+					s.bar.openMenu(index)
+				}
+			}
+		case p.depth > 0:
+			s.closeFrom(p.depth)
+		default:
+			s.close()
+		}
+	default:
+		if event.Key == 0 && event.Rune != 0 {
+			if index := findMnemonic(p.items, event.Rune); index >= 0 {
+				s.activate(p, index)
+			}
+		}
+	}
+}
+
+// moveCursor moves the cursor of the menu and closes its submenu
+// @parma index: index of the item, ignored when it is negative
+func (p *menuPopup) moveCursor(index int) {
+	if index < 0 || index == p.cursor {
+		return
+	}
+	p.cursor = index
+	p.session.closeFrom(p.depth + 1)
+	Render()
+}
+
+// handleMouse follows the mouse over the items, an item with a submenu opens it when the mouse moves over it and the left button activates an item
+func (p *menuPopup) handleMouse() {
+	ensureLayout()
+	content := p.contentRect()
+	index := p.mouse.Y - content.top
+	if !content.contains(p.mouse.X, p.mouse.Y) || index >= len(p.items) {
+		return
+	}
+	item := p.items[index]
+	switch {
+	case p.mouse.Action == MouseMove:
+		if item.selectable() && index != p.cursor {
+			p.moveCursor(index)
+			p.session.openSubmenu(p, false)
+		}
+	case p.mouse.Action == MousePress && p.mouse.Button == MouseLeft:
+		p.session.activate(p, index)
+	}
+}
+
+// menuColumns measures the columns of a menu
+// @parma items: the items
+// @return the width of the check mark column, the labels, the accelerators and the submenu arrows, unused columns are 0
+func menuColumns(items []*MenuItem) (int, int, int, int) {
+	mark, label, accelerator, arrow := 0, 0, 0, 0
+	for _, item := range items {
+		if item.Separator {
+			continue
+		}
+		if item.Checkable {
+			mark = 2
+		}
+		runes, _ := mnemonicLabel(item.Label)
+		label = maxInt(label, len(runes))
+		if text := len([]rune(item.Accelerator.Text)); text > 0 {
+			accelerator = maxInt(accelerator, text+2) // Two cells between the label and the accelerator
+		}
+		if len(item.Items) > 0 {
+			arrow = 2
+		}
+	}
+	return mark, label, accelerator, arrow
+}
+
+// menuWidth returns the width of a menu with its border and one cell of padding on each side
+// @parma items: the items
+func menuWidth(items []*MenuItem) int {
+	mark, label, accelerator, arrow := menuColumns(items)
+	return mark + label + accelerator + arrow + 4
+}
+
+// menuRender paints a menu, the cursor item is reversed, disabled items are dimmed and separators are drawn across the menu
+// @parma p: pointer to the menu struct
+// @return the render result of the node
+func menuRender(p *menuPopup) bool {
+	if p.clip.empty() {
+		return false
+	}
+	style := p.style
+	boxDrawing(&p.Canvas, style)
+
+	content := p.contentRect()
+	clip := content.intersect(p.clip)
+	mark, _, _, arrow := menuColumns(p.items)
+	for index, item := range p.items {
+		y := content.top + index
+		if item.Separator {
+			for x := content.left; x < content.right; x++ {
+				screenBuffer.set(x, y, styleCell(style, menuSeparatorRune), clip)
+			}
+			continue
+		}
+
+		rowStyle := style
+		if item.Disabled {
+			rowStyle.Attribute |= AttrDim
+		}
+		if index == p.cursor {
+			rowStyle.Attribute |= AttrReverse
+		}
+		for x := content.left; x < content.right; x++ {
+			screenBuffer.set(x, y, styleCell(rowStyle, ' '), clip)
+		}
+
+		x := content.left + 1
+		if item.Checked {
+			screenBuffer.set(x, y, styleCell(rowStyle, menuCheckRune), clip)
+		}
+		x += mark
+		label, mnemonic := mnemonicLabel(item.Label)
+		for column, char := range label {
+			labelCell := styleCell(rowStyle, char)
+			if column == mnemonic && !item.Disabled {
+				labelCell.attribute |= AttrUnderline
+			}
+			screenBuffer.set(x+column, y, labelCell, clip)
+		}
+
+		accelerator := []rune(item.Accelerator.Text)
+		right := content.right - 1 - arrow
+		for column, char := range accelerator {
+			screenBuffer.set(right-len(accelerator)+column, y, styleCell(rowStyle, char), clip)
+		}
+		if len(item.Items) > 0 {
+			screenBuffer.set(content.right-2, y, styleCell(rowStyle, menuSubmenuRune), clip)
+		}
+	}
+	return true
+}
+
+// menuSpan the cells of a title of a menu bar, relative to the left of the bar
+type menuSpan struct {
+	start int //first cell of the title
+	end   int //cell after the title
+}
+
+// MenuBar a bar of menu titles, usually on the first row of the screen. A title opens its menu below it when it is clicked, when
+// Alt and its mnemonic are pressed or with Enter and Down while the bar is selected, F10 opens the first menu. Left and Right move
+// between the menus while one is open and the accelerators of the items work from anywhere while no modal is shown
+type MenuBar struct {
+	Quadrilateral              //inherited struct
+	menus         []*MenuItem  //the menus, the items of a menu are its Items
+	highlight     int          //index of the title highlighted while the bar is selected
+	openIndex     int          //index of the open menu, -1 when no menu is open
+	session       *menuSession //the open menus, nil when no menu is open
+}
+
+// CreateMenuBar Creates a menu bar that is one row high and fills the width of its parent
+// @parma name: the name of the node, does not force uniqueness menus: the menus, their Items are shown when they open
+// @return the menu bar, loaded into each global repository before it returns
+func CreateMenuBar(name string, menus ...*MenuItem) *MenuBar {
+	element := new(MenuBar)
+	mountQuadrilateral(element, &element.Quadrilateral, MenuBarTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFill, Height: 1}
+	element.style.Color = BlackColor
+	element.style.BackGroundColor = WhiteBackGroundColor
+	element.menus = menus
+	element.openIndex = -1
+	menuBars = append(menuBars, element)
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		bar := node.(*MenuBar)
+		if !bar.navigateByKey() {
+			forwardKey(bar)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		bar := node.(*MenuBar)
+		if bar.mouse.Button == MouseLeft && bar.mouse.Action == MousePress {
+			bar.openMenu(bar.menuAt(bar.mouse.X, bar.mouse.Y))
+		}
+	})
+	element.AddEventListener(OnRemove, func(node Node, origen Node) {
+		for index, bar := range menuBars {
+			if bar == node.(*MenuBar) {
+				menuBars = append(menuBars[:index], menuBars[index+1:]...)
+				break
+			}
+		}
+	})
+
+	return element
+}
+
+// SetMenus replaces the menus, an open menu is closed
+// @parma menus: the menus
+func (b *MenuBar) SetMenus(menus ...*MenuItem) error {
+	if b.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if b.session != nil {
+		b.session.close()
+	}
+	b.menus = menus
+	b.highlight = 0
+	Render()
+	return nil
+}
+
+// GetMenus returns the menus
+func (b *MenuBar) GetMenus() ([]*MenuItem, error) {
+	if b.unMount {
+		return b.menus, errors.New(OperatingEmptyNodeError)
+	}
+	return b.menus, nil
+}
+
+// OpenMenu opens a menu below its title and selects it
+// @parma index: index of the menu
+func (b *MenuBar) OpenMenu(index int) error {
+	if b.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	b.openMenu(index)
+	return nil
+}
+
+// CloseMenu closes the open menu and its submenus
+func (b *MenuBar) CloseMenu() error {
+	if b.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if b.session != nil {
+		b.session.close()
+	}
+	return nil
+}
+
+// openMenu opens a menu, the open menu is closed first. A menu without items runs like an item
+// @parma index: index of the menu, ignored when it is out of range or disabled
+func (b *MenuBar) openMenu(index int) {
+	if index < 0 || index >= len(b.menus) || !b.menus[index].selectable() {
+		return
+	}
+	if b.session != nil {
+		b.session.close()
+	}
+	b.highlight = index
+	menu := b.menus[index]
+	if len(menu.Items) == 0 {
+		runItem(menu)
+		return
+	}
+	ensureLayout()
+	bar := b.contentRect()
+	b.openIndex = index
+	b.session = openMenuSession(menu.Items, bar.left+b.menuSpans()[index].start, b.rect.bottom, b, func() {
+		b.session = nil
+		b.openIndex = -1
+		Render()
+	})
+}
+
+// switchMenu opens the next or the previous menu that has items
+// @parma step: 1 for the next menu, -1 for the previous one
+func (b *MenuBar) switchMenu(step int) {
+	count := len(b.menus)
+	for offset := 1; offset < count; offset++ {
+		index := ((b.openIndex+step*offset)%count + count) % count
+		if b.menus[index].selectable() && len(b.menus[index].Items) > 0 {
+			b.openMenu(index)
+			return
+		}
+	}
+}
+
+// navigateByKey moves the highlighted title with Left and Right and opens it with Enter, Space or Down while the bar is selected
+// @return whether the key was used
+func (b *MenuBar) navigateByKey() bool {
+	if len(b.menus) == 0 {
+		return false
+	}
+	switch b.KeyEvent.Key {
+	case KeyArrowLeft:
+		b.highlight = maxInt(nextSelectable(b.menus, b.highlight, -1), 0)
+	case KeyArrowRight:
+		b.highlight = maxInt(nextSelectable(b.menus, b.highlight, 1), 0)
+	case KeyEnter, KeySpace, KeyArrowDown:
+		b.openMenu(b.highlight)
+	default:
+		return false
+	}
+	Render()
+	return true
+}
+
+// menuSpans places the titles in the bar
+func (b *MenuBar) menuSpans() []menuSpan {
+	spans := make([]menuSpan, len(b.menus))
+	x := 0
+	for index, menu := range b.menus {
+		label, _ := mnemonicLabel(menu.Label)
+		spans[index] = menuSpan{start: x, end: x + len(label) + 2} // One cell of padding on each side
+		x = spans[index].end
+	}
+	return spans
+}
+
+// menuAt returns the index of the title at a cell, -1 when there is none
+// @parma x: x-axis position y: y-axis position
+func (b *MenuBar) menuAt(x, y int) int {
+	ensureLayout()
+	bar := b.contentRect()
+	if y != bar.top || !b.clip.contains(x, y) {
+		return -1
+	}
+	for index, span := range b.menuSpans() {
+		if x-bar.left >= span.start && x-bar.left < span.end {
+			return index
+		}
+	}
+	return -1
+}
+
+// menuBarRender paints the titles of a menu bar, the open title and the highlighted title of a selected bar are reversed
+// @parma b: pointer to the menu bar struct
+// @return the render result of the node
+func menuBarRender(b *MenuBar) bool {
+	if b.clip.empty() {
+		return false
+	}
+	style := b.style
+	boxDrawing(&b.Canvas, style)
+
+	bar := b.contentRect()
+	clip := bar.intersect(b.clip)
+	focused := SelectNode == Node(b)
+	for index, span := range b.menuSpans() {
+		menu := b.menus[index]
+		titleStyle := style
+		if menu.Disabled {
+			titleStyle.Attribute |= AttrDim
+		}
+		if index == b.openIndex || (focused && index == b.highlight) {
+			titleStyle.Attribute |= AttrReverse
+		}
+		label, mnemonic := mnemonicLabel(menu.Label)
+		screenBuffer.set(bar.left+span.start, bar.top, styleCell(titleStyle, ' '), clip)
+		for column, char := range label {
+			titleCell := styleCell(titleStyle, char)
+			if column == mnemonic && !menu.Disabled {
+				titleCell.attribute |= AttrUnderline
+			}
+			screenBuffer.set(bar.left+span.start+1+column, bar.top, titleCell, clip)
+		}
+		screenBuffer.set(bar.left+span.end-1, bar.top, styleCell(titleStyle, ' '), clip)
+	}
+	return true
+}
+
+// ContextMenu a menu that is opened at a cell, usually where the right button is pressed. It is not a node of the tree,
+// its menus are shown above everything else and close when an item is activated, with Esc or with a click outside them
+type ContextMenu struct {
+	items   []*MenuItem  //the items
+	session *menuSession //the open menus, nil when the menu is closed
+}
+
+// CreateContextMenu Creates a closed context menu
+// @parma items: the items
+// @return the context menu
+func CreateContextMenu(items ...*MenuItem) *ContextMenu {
+	return &ContextMenu{items: items}
+}
+
+// SetItems replaces the items, they are used the next time the menu opens
+// @parma items: the items
+func (cm *ContextMenu) SetItems(items ...*MenuItem) {
+	cm.items = items
+}
+
+// GetItems returns the items
+func (cm *ContextMenu) GetItems() []*MenuItem {
+	return cm.items
+}
+
+// Open opens the menu with its top left corner at a cell, the menu is moved to stay on the screen and an open menu is closed first
+// @parma x: x-axis position y: y-axis position
+func (cm *ContextMenu) Open(x, y int) error {
+	if Body == nil {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	cm.Close()
+	if len(cm.items) == 0 {
+		return nil
+	}
+	cm.session = openMenuSession(cm.items, x, y, nil, func() {
+		cm.session = nil
+	})
+	return nil
+}
+
+// Close closes the menu and its submenus
+func (cm *ContextMenu) Close() {
+	if cm.session != nil {
+		cm.session.close()
+	}
+}
+
+// IsOpen reports whether the menu is open
+func (cm *ContextMenu) IsOpen() bool {
+	return cm.session != nil
+}
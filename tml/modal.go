@@ -0,0 +1,235 @@
+package tml
+
+import "errors"
+
+// modalStack the shown modals from the bottom to the top, only the top modal receives the keys and the mouse
+var modalStack []*Modal
+
+// Modal an overlay that covers the whole screen, dims everything beneath it and holds a content node in its center.
+// While a modal is shown the nodes beneath it cannot be selected, clicked or reached by keys, Tab and Shift+Tab move the
+// selection between the nodes of the modal that listen to keys and Esc closes it. The selection from before the modal is restored when it closes
+type Modal struct {
+	Quadrilateral          //inherited struct
+	content       Node     //the node shown in the center
+	previous      Node     //the selected node when the modal was shown
+	onClose       []func() //called after the modal is closed
+	closeOnEsc    bool     //whether Esc closes the modal
+	dim           bool     //whether the cells beneath the modal are dimmed
+}
+
+// ShowModal shows a node in the center of a new modal above everything else, including the modals that are already shown.
+// The first descendant of the node that listens to keys is selected, the node is removed when the modal closes
+// @parma node: the content of the modal onClose: called after the modal is closed, however it is closed
+// @return the modal
+func ShowModal(node Node, onClose ...func()) (*Modal, error) {
+	if Body == nil || node == nil || node.isUnMount() {
+		return nil, errors.New(OperatingEmptyNodeError)
+	}
+	position, _ := node.GetPosition()
+	position.X, position.Y = 0, 0
+	node.SetPosition(position, CanvasAnchor{Horizontal: AnchorCenter, Vertical: AnchorCenter})
+	return showOverlay(node, true, onClose...), nil
+}
+
+// showOverlay shows a node in a new modal at the position of the node, menus use it without dimming
+// @parma node: the content of the modal dim: whether the cells beneath the modal are dimmed onClose: called after the modal is closed
+// @return the modal
+func showOverlay(node Node, dim bool, onClose ...func()) *Modal {
+	element := new(Modal)
+	mountQuadrilateral(element, &element.Quadrilateral, ModalTag, "modal")
+	element.content = node
+	element.previous = SelectNode
+	element.onClose = onClose
+	element.closeOnEsc = true
+	element.dim = dim
+	element.style.BackGroundColor = NoColor
+	element.SetPosition(CanvasPosition{ZIndex: ModalZIndex + uint32(len(modalStack)), Overlay: true},
+		CanvasAnchor{Horizontal: AnchorStretch, Vertical: AnchorStretch})
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		node.(*Modal).handleKey()
+	})
+	consume := func(node Node, origen Node) {} // The reports of the mouse stop at the modal so the nodes beneath it do not receive them
+	element.AddEventListener(OnMouse, consume)
+	element.AddEventListener(OnWheel, consume)
+
+	element.Insert(node)
+	Body.Insert(element)
+
+	modalStack = append(modalStack, element)
+	if focusable := focusableNodes(node); len(focusable) > 0 {
+		Select(focusable[0])
+	} else {
+		Select(element)
+	}
+	Render()
+	return element
+}
+
+// CloseModal closes the topmost modal
+func CloseModal() error {
+	if len(modalStack) == 0 {
+		return nil
+	}
+	return modalStack[len(modalStack)-1].Close()
+}
+
+// TopModal returns the topmost modal, nil when no modal is shown
+func TopModal() *Modal {
+	if len(modalStack) == 0 {
+		return nil
+	}
+	return modalStack[len(modalStack)-1]
+}
+
+// Close removes the modal and its content, the selection goes back to the node that was selected when the modal was shown
+func (m *Modal) Close() error {
+	if m.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	index := -1
+	for stackIndex, modal := range modalStack {
+		if modal == m {
+			index = stackIndex
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+	modalStack = append(modalStack[:index], modalStack[index+1:]...)
+
+	if index < len(modalStack) { // A modal above hands its selection back to the one that was selected before this modal
+		above := modalStack[index]
+		if nodePath(above.previous).contains(m) {
+			above.previous = m.previous
+		}
+	}
+	if !m.content.isUnMount() {
+		m.content.Remove()
+	}
+	m.Remove()
+
+	if index == len(modalStack) { // The top modal was closed
+		switch {
+		case m.previous == nil:
+			Select(Body)
+		case m.previous.isUnMount():
+			backSelect(m.previous)
+		default:
+			Select(m.previous)
+		}
+	}
+	Render()
+	for _, callBack := range m.onClose {
+		if callBack != nil {
+			callBack()
+		}
+	}
+	return nil
+}
+
+// GetContent returns the node shown in the center of the modal
+func (m *Modal) GetContent() (Node, error) {
+	if m.unMount {
+		return m.content, errors.New(OperatingEmptyNodeError)
+	}
+	return m.content, nil
+}
+
+// SetCloseOnEsc sets whether Esc closes the modal, it does by default
+// @parma closeOnEsc: whether Esc closes the modal
+func (m *Modal) SetCloseOnEsc(closeOnEsc bool) error {
+	if m.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	m.closeOnEsc = closeOnEsc
+	return nil
+}
+
+// handleKey uses the keys that the nodes of the modal do not use, they are never passed to the nodes beneath the modal
+func (m *Modal) handleKey() {
+	event := m.KeyEvent
+	switch {
+	case event.Key == KeyEsc && event.Rune == 0:
+		if m.closeOnEsc {
+			m.Close()
+		}
+	case event.Key == KeyTab && KeyModifier&ModShift != 0, event.Key == KeyArrowLeft, event.Key == KeyArrowUp:
+		m.moveFocus(-1)
+	case event.Key == KeyTab, event.Key == KeyArrowRight, event.Key == KeyArrowDown:
+		m.moveFocus(1)
+	}
+}
+
+// moveFocus selects the next or the previous node of the modal that listens to keys, the selection wraps around
+// @parma step: 1 for the next node, -1 for the previous one
+func (m *Modal) moveFocus(step int) {
+	focusable := focusableNodes(m.content)
+	if len(focusable) == 0 {
+		return
+	}
+	current := -1
+	for index, node := range focusable {
+		if node == SelectNode {
+			current = index
+		}
+	}
+	if current < 0 && step < 0 {
+		current = 0
+	}
+	Select(focusable[(current+step+len(focusable))%len(focusable)])
+}
+
+// focusableNodes collects a node and its displayed descendants that listen to keys, in the order of the tree
+// @parma node: the first node of the search
+func focusableNodes(node Node) NodeStack {
+	focusable := NodeStack{}
+	var visit func(node Node)
+	visit = func(node Node) {
+		style, _ := node.GetStyle()
+		if node.isUnMount() || !style.Display {
+			return
+		}
+		if hasEvent(node, OnKeyBord) {
+			focusable = append(focusable, node)
+		}
+		children, _ := node.GetChildren()
+		for _, child := range children {
+			visit(child)
+		}
+	}
+	visit(node)
+	return focusable
+}
+
+// modalAllowsSelect reports whether a node may be selected, only the top modal and its descendants can be selected while modals
+// are shown and a click on the dimmed area keeps the selection of the modal
+// @parma node: the node to select
+func modalAllowsSelect(node Node) bool {
+	top := TopModal()
+	if top == nil {
+		return true
+	}
+	if !nodePath(node).contains(top) {
+		return false
+	}
+	return node != Node(top) || SelectNode == Node(top) || !nodePath(SelectNode).contains(top)
+}
+
+// modalRender dims the cells painted beneath a dimming modal and hides the terminal cursor placed by the nodes beneath it
+// @parma m: pointer to the modal struct
+// @return the render result of the node
+func modalRender(m *Modal) bool {
+	if m.clip.empty() {
+		return false
+	}
+	for y := m.clip.top; m.dim && y < m.clip.bottom; y++ {
+		for x := m.clip.left; x < m.clip.right; x++ {
+			if target := screenBuffer.get(x, y); target != nil {
+				target.attribute |= AttrDim
+			}
+		}
+	}
+	screenBuffer.cursor = false
+	return true
+}
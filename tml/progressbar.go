@@ -0,0 +1,254 @@
+package tml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// progressEighths the partial blocks of a progress bar from one eighth to seven eighths of a cell
+var progressEighths = []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+// Progress bar characters
+const (
+	progressFullRune = '█'
+)
+
+// ProgressBar a determinate progress bar on a single row: the text of the node, the bar, the percentage and the estimated time left.
+// The bar is drawn with eighth blocks so it moves by an eighth of a cell. The estimate assumes the progress keeps the average rate
+// since the bar was created or reset
+type ProgressBar struct {
+	Quadrilateral           //inherited struct
+	progress      float64   //the done fraction from 0 to 1
+	barColor      Color     //color of the done part of the bar
+	trackColor    Color     //color of the rest of the bar
+	showPercent   bool      //whether the percentage is shown after the bar
+	showETA       bool      //whether the estimated time left is shown after the bar
+	started       time.Time //when the progress started, the estimate is measured from it
+	lastTick      time.Time //when the estimate was last drawn
+}
+
+// CreateProgressBar Creates an empty progress bar that is one row high and fills the width of its parent, the percentage is shown
+// @parma name: the name of the node, does not force uniqueness label: the text shown before the bar
+// @return the progress bar, loaded into each global repository before it returns
+func CreateProgressBar(name string, label string) *ProgressBar {
+	element := new(ProgressBar)
+	mountQuadrilateral(element, &element.Quadrilateral, ProgressBarTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFill, Height: 1}
+	element.text = label
+	element.spans = PlainText(label)
+	element.barColor = GreenColor
+	element.trackColor = BrightBlackColor
+	element.showPercent = true
+	element.started = time.Now()
+	element.AddEventListener(OnRemove, func(node Node, origen Node) {
+		stopAnimation(node.(*ProgressBar))
+	})
+	return element
+}
+
+// SetProgress sets the done fraction
+// @parma progress: the fraction from 0 to 1, it is clamped
+func (pb *ProgressBar) SetProgress(progress float64) error {
+	if pb.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if math.IsNaN(progress) {
+		progress = 0
+	}
+	progress = math.Max(math.Min(progress, 1), 0)
+	if progress != pb.progress {
+		pb.progress = progress
+		pb.updateAnimation()
+		Render()
+	}
+	return nil
+}
+
+// SetValue sets the progress as a number of done units out of a total, such as bytes
+// @parma done: the done units total: all the units, the bar is empty when it is not positive
+func (pb *ProgressBar) SetValue(done, total float64) error {
+	if total <= 0 {
+		return pb.SetProgress(0)
+	}
+	return pb.SetProgress(done / total)
+}
+
+// GetProgress returns the done fraction
+func (pb *ProgressBar) GetProgress() (float64, error) {
+	if pb.unMount {
+		return pb.progress, errors.New(OperatingEmptyNodeError)
+	}
+	return pb.progress, nil
+}
+
+// Reset empties the bar and starts measuring the estimate again
+func (pb *ProgressBar) Reset() error {
+	if pb.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	pb.progress = 0
+	pb.started = time.Now()
+	pb.updateAnimation()
+	Render()
+	return nil
+}
+
+// SetShowPercent sets whether the percentage is shown after the bar
+// @parma show: whether the percentage is shown
+func (pb *ProgressBar) SetShowPercent(show bool) error {
+	if pb.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	pb.showPercent = show
+	Render()
+	return nil
+}
+
+// SetShowETA sets whether the estimated time left is shown after the bar, the estimate counts down every second while it is shown
+// @parma show: whether the estimate is shown
+func (pb *ProgressBar) SetShowETA(show bool) error {
+	if pb.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	pb.showETA = show
+	pb.updateAnimation()
+	Render()
+	return nil
+}
+
+// counting reports whether the estimate is counting down, which is while it is shown and the progress is started but not done
+func (pb *ProgressBar) counting() bool {
+	return pb.showETA && pb.progress > 0 && pb.progress < 1
+}
+
+// updateAnimation animates the bar while the estimate is counting down, a bar that is not displayed stops in tick and starts again when it is drawn
+func (pb *ProgressBar) updateAnimation() {
+	if pb.counting() {
+		startAnimation(pb)
+	} else {
+		stopAnimation(pb)
+	}
+}
+
+// due reports whether the estimate was drawn a second ago, a bar that is no longer displayed is due once so the render stops its animation
+// @parma now: the time of the check
+func (pb *ProgressBar) due(now time.Time) bool {
+	return !pb.displayed || now.Sub(pb.lastTick) >= time.Second
+}
+
+// tick records when the estimate is drawn, the estimate itself is measured again whenever the bar is drawn. The animation stops
+// when the estimate no longer counts down or the bar was not displayed by the last layout
+// @parma now: the time of the frame
+func (pb *ProgressBar) tick(now time.Time) {
+	if !pb.counting() || !pb.displayed {
+		stopAnimation(pb)
+		return
+	}
+	pb.lastTick = now
+}
+
+// SetBarColors sets the colors of the bar
+// @parma bar: color of the done part track: color of the rest of the bar
+func (pb *ProgressBar) SetBarColors(bar, track Color) error {
+	if pb.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	pb.barColor, pb.trackColor = bar, track
+	Render()
+	return nil
+}
+
+// GetETA returns the estimated time left, it is negative while nothing is done because it is unknown
+func (pb *ProgressBar) GetETA() (time.Duration, error) {
+	eta, known := pb.eta()
+	if !known {
+		eta = -1
+	}
+	if pb.unMount {
+		return eta, errors.New(OperatingEmptyNodeError)
+	}
+	return eta, nil
+}
+
+// eta returns the estimated time left, false when it is unknown because nothing is done yet
+func (pb *ProgressBar) eta() (time.Duration, bool) {
+	if pb.progress <= 0 {
+		return 0, false
+	}
+	elapsed := time.Since(pb.started)
+	return time.Duration(float64(elapsed) * (1 - pb.progress) / pb.progress), true
+}
+
+// formatETA formats an estimate as m:ss or h:mm:ss, --:-- when it is unknown
+// @parma eta: the estimate known: whether the estimate is known
+func formatETA(eta time.Duration, known bool) string {
+	if !known {
+		return "--:--"
+	}
+	seconds := int(eta.Round(time.Second) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// status returns the text shown after the bar
+func (pb *ProgressBar) status() string {
+	status := ""
+	if pb.showPercent {
+		status += fmt.Sprintf(" %3d%%", int(pb.progress*100))
+	}
+	if pb.showETA {
+		status += " ETA " + formatETA(pb.eta())
+	}
+	return status
+}
+
+// progressBarRender paints the text, the bar and the status of a progress bar, the bar takes the cells the others leave
+// @parma pb: pointer to the progress bar struct
+// @return the render result of the node
+func progressBarRender(pb *ProgressBar) bool {
+	if pb.clip.empty() {
+		return false
+	}
+	if pb.counting() { // A bar that stopped while it was not displayed counts down again once it is drawn
+		startAnimation(pb)
+	}
+	style := pb.style
+	boxDrawing(&pb.Canvas, style)
+
+	content := pb.contentRect()
+	clip := content.intersect(pb.clip)
+	x := content.left
+	if style.ShowText && pb.spans.Len() > 0 {
+		for _, labelRune := range pb.spans.runes() {
+			screenBuffer.set(x, content.top, spanCell(style, pb.spans[labelRune.span], labelRune.char), clip)
+			x++
+		}
+		x++ // A space between the text and the bar
+	}
+
+	status := []rune(pb.status())
+	width := maxInt(content.right-len(status)-x, 0)
+	eighths := int(math.Round(pb.progress * float64(width*8)))
+	barStyle := style
+	barStyle.Color, barStyle.BackGroundColor = pb.barColor, pb.trackColor
+	for column := 0; column < width; column++ {
+		char := ' '
+		switch filled := eighths - column*8; {
+		case filled >= 8:
+			char = progressFullRune
+		case filled > 0:
+			char = progressEighths[filled-1]
+		}
+		screenBuffer.set(x+column, content.top, styleCell(barStyle, char), clip)
+	}
+	x += width
+
+	for column, char := range status {
+		screenBuffer.set(x+column, content.top, styleCell(style, char), clip)
+	}
+	return true
+}
@@ -9,9 +9,10 @@ import (
 
 // Quadrilateral a square Canvas
 type Quadrilateral struct {
-	Canvas                     //inherited struct
-	keyboard.KeyEvent          //store the data generated by key bord events
-	props             sync.Map //props that store custom information
+	Canvas                       //inherited struct
+	keyboard.KeyEvent            //store the data generated by key bord events
+	mouse             MouseEvent //store the data generated by mouse events
+	props             sync.Map   //props that store custom information
 }
 
 func (ql *Quadrilateral) SetProps(key, value string) error {
@@ -35,26 +36,64 @@ func (ql *Quadrilateral) GetKeyBord() (keyboard.KeyEvent, error) {
 	return ql.KeyEvent, nil
 }
 
+func (ql *Quadrilateral) setMouse(event MouseEvent) {
+	ql.mouse = event
+}
+
+func (ql *Quadrilateral) GetMouse() (MouseEvent, error) {
+	if ql.unMount {
+		return ql.mouse, errors.New(OperatingEmptyNodeError)
+	}
+	return ql.mouse, nil
+}
+
 func (ql *Quadrilateral) SetText(text string) error {
 	if ql.unMount {
 		return errors.New(OperatingEmptyNodeError)
 	}
-	if ql.text != text {
+	spans := PlainText(text)
+	changed := !reflect.DeepEqual(ql.spans, spans) // Styled spans of the same text are replaced by plain text too
+	ql.text = text
+	ql.spans = spans
+	if changed {
+		invalidateLayout() // Nodes sized to their content follow the text
 		Render()
-		triggerEvent(ql, OnInput, ql)
+		triggerEvent(ql.self, OnInput, ql.self)
+	}
+	return nil
+}
+
+func (ql *Quadrilateral) SetStyledText(text StyledText) error {
+	if ql.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	changed := !reflect.DeepEqual(ql.spans, text)
+	ql.text = text.String()
+	ql.spans = text
+	if changed {
+		invalidateLayout()
+		Render()
+		triggerEvent(ql.self, OnInput, ql.self)
 	}
-	ql.text = text
 	return nil
 }
 
+func (ql *Quadrilateral) GetText() (StyledText, error) {
+	if ql.unMount {
+		return ql.spans, errors.New(OperatingEmptyNodeError)
+	}
+	return ql.spans, nil
+}
+
 func (ql *Quadrilateral) Insert(node ...Node) error {
 	if ql.unMount {
 		return errors.New(OperatingEmptyNodeError)
 	}
 	ql.children = append(ql.children, node...)
 	for _, childNode := range node {
-		childNode.setParent(ql)
+		childNode.setParent(ql.self)
 	}
+	invalidateLayout()
 	Render()
 	return nil
 }
@@ -69,19 +108,20 @@ func (ql *Quadrilateral) setParent(node Node) error {
 		oldStyle, _ := oldParent.GetStyle()
 		newStyle, _ := node.GetStyle()
 		if oldStyle.Display != newStyle.Display {
-			displayEventTrigger(ql, node, newStyle.Display, true)
+			displayEventTrigger(ql.self, node, newStyle.Display, true)
 		}
 	} else if oldParent != nil && node == nil {
-		displayEventTrigger(ql, nil, false, true)
+		displayEventTrigger(ql.self, nil, false, true)
 	} else if oldParent == nil && node != nil {
 		newStyle, _ := node.GetStyle()
 		if newStyle.Display {
-			displayEventTrigger(ql, node, true, true)
+			displayEventTrigger(ql.self, node, true, true)
 		}
 	}
 
 	ql.parent = node
 
+	invalidateLayout()
 	Render()
 
 	if node == nil {
@@ -96,25 +136,26 @@ func (ql *Quadrilateral) SetVolume(volume CanvasVolume) error {
 		return errors.New(OperatingEmptyNodeError)
 	}
 
-	if ql.volume.Width != volume.Width || ql.volume.Height != volume.Height {
-		triggerEvent(ql, OnSizeChange, ql)
+	oldVolume := ql.volume
+	ql.volume = volume
+	if oldVolume != volume {
+		invalidateLayout()
+		triggerEvent(ql.self, OnSizeChange, ql.self)
 		Render()
 		for _, child := range ql.children { // Handle adaptive numeric events in child nodes
 			autoSizeChangeTrigger(child, ql, true)
 		}
 	}
-
-	ql.volume = volume
 	return nil
 }
 
-func (ql *Quadrilateral) SetPosition(position CanvasPosition, pType ...CanvasPositionType) error {
+func (ql *Quadrilateral) SetPosition(position CanvasPosition, anchor ...CanvasAnchor) error {
 	if ql.unMount {
 		return errors.New(OperatingEmptyNodeError)
 	}
 
 	if position.X != ql.position.X || position.Y != ql.position.Y { // Attempt to trigger an event
-		triggerEvent(ql, OnMove, ql)
+		triggerEvent(ql.self, OnMove, ql.self)
 		for _, child := range ql.children { // Handle adaptive numeric events in child nodes
 			autoSizeChangeTrigger(child, ql, true)
 		}
@@ -126,22 +167,30 @@ func (ql *Quadrilateral) SetPosition(position CanvasPosition, pType ...CanvasPos
 
 	oldPosition := ql.position
 
+	if position.ZIndex != oldPosition.ZIndex { // Move the node to its new level of the render stack
+		delNodeFromRenderStack(ql.self)
+	}
+
 	ql.position = CanvasPosition{
-		X:      position.X,
-		Y:      position.Y,
-		totalX: ql.position.totalX,
-		totalY: ql.position.totalY,
-		Type:   position.Type,
-		ZIndex: position.ZIndex,
+		X:       position.X,
+		Y:       position.Y,
+		Anchor:  position.Anchor,
+		ZIndex:  position.ZIndex,
+		Overlay: position.Overlay,
+	}
+
+	if position.ZIndex != oldPosition.ZIndex {
+		loadNodeToRenderStack(ql.self)
 	}
 
-	typeLen := len(pType)
+	anchorLen := len(anchor)
 
-	if typeLen > 0 {
-		ql.position.Type = pType[typeLen-1]
+	if anchorLen > 0 {
+		ql.position.Anchor = anchor[anchorLen-1]
 	}
 
 	if !reflect.DeepEqual(oldPosition, ql.position) {
+		invalidateLayout()
 		Render()
 	}
 
@@ -153,11 +202,14 @@ func (ql *Quadrilateral) SetStyle(style CanvasStyle) error {
 		return errors.New(OperatingEmptyNodeError)
 	}
 	if style.Display != ql.style.Display {
-		displayEventTrigger(ql, ql, style.Display, true) // Trigger show and hide events and trigger recursively
+		displayEventTrigger(ql.self, ql, style.Display, true) // Trigger show and hide events and trigger recursively
 	}
 
 	oldStyle := ql.style
 	ql.style = style
+	if layoutStyleChanged(oldStyle, style) {
+		invalidateLayout()
+	}
 	if !reflect.DeepEqual(oldStyle, style) {
 		Render()
 	}
@@ -206,7 +258,7 @@ func (ql *Quadrilateral) AddEventListener(event uint8, callback EventCallBack) e
 	if ql.unMount {
 		return errors.New(OperatingEmptyNodeError)
 	}
-	addEvent(ql, event, callback)
+	addEvent(ql.self, event, callback)
 	return nil
 }
 
@@ -214,7 +266,7 @@ func (ql *Quadrilateral) DeleteEventListener(event uint8, callback EventCallBack
 	if ql.unMount {
 		return errors.New(OperatingEmptyNodeError)
 	}
-	untieEvent(ql, event, callback)
+	untieEvent(ql.self, event, callback)
 	return nil
 }
 
@@ -240,6 +292,7 @@ func (ql *Quadrilateral) RemoveChildren(node Node) error {
 	for index, qlNode := range ql.children {
 		if childAttr, _ := qlNode.GetAttr(); childAttr.Key == attr.Key {
 			ql.children = append(ql.children[:index], ql.children[index+1:]...)
+			invalidateLayout()
 			if !qlNode.isUnMount() {
 				qlNode.Remove()
 			}
@@ -257,18 +310,35 @@ func (ql *Quadrilateral) Remove() error { // Trigger show and hide events and tr
 		return errors.New(DeleteTopLeveNodeError)
 	}
 	nodeParent, _ := ql.GetParent()
-	delNodeFromRenderStack(ql)
-	delNodeFromNameIndex(ql)
-	delNodeFromBase(ql)
-	deleteEvent(ql)
-	triggerEvent(ql, OnRemove, ql)
+	delNodeFromRenderStack(ql.self)
+	delNodeFromNameIndex(ql.self)
+	delNodeFromBase(ql.self)
+	triggerEvent(ql.self, OnRemove, ql.self) // Listeners are told before they are deleted
+	deleteEvent(ql.self)
 	if nodeParent != nil && !nodeParent.isUnMount() {
-		nodeParent.RemoveChildren(ql)
+		nodeParent.RemoveChildren(ql.self)
 	}
 	ql.unMount = true
+	invalidateLayout()
 	return nil
 }
 
+func (ql *Quadrilateral) GetLayout() (CanvasLayout, error) {
+	if ql.unMount {
+		return CanvasLayout{}, errors.New(OperatingEmptyNodeError)
+	}
+	ensureLayout()
+	return ql.canvasLayout(), nil
+}
+
+func (ql *Quadrilateral) GetBounds() (CanvasBounds, error) {
+	if ql.unMount {
+		return CanvasBounds{}, errors.New(OperatingEmptyNodeError)
+	}
+	ensureLayout()
+	return ql.rect.bounds(), nil
+}
+
 func (ql *Quadrilateral) isUnMount() bool {
 	return ql.unMount
 }
@@ -0,0 +1,87 @@
+package tml
+
+// canvasRect a rectangle of cells on the screen, left and top are inclusive, right and bottom are exclusive
+type canvasRect struct {
+	left   int
+	top    int
+	right  int
+	bottom int
+}
+
+// screenRect returns the rectangle of the whole terminal
+func screenRect() canvasRect {
+	return canvasRect{right: SysWidth, bottom: SysHeight}
+}
+
+// width returns the width of the rectangle, never negative
+func (r canvasRect) width() int {
+	if r.right < r.left {
+		return 0
+	}
+	return r.right - r.left
+}
+
+// height returns the height of the rectangle, never negative
+func (r canvasRect) height() int {
+	if r.bottom < r.top {
+		return 0
+	}
+	return r.bottom - r.top
+}
+
+// empty reports whether the rectangle contains no cell
+func (r canvasRect) empty() bool {
+	return r.right <= r.left || r.bottom <= r.top
+}
+
+// contains reports whether the cell is inside the rectangle
+func (r canvasRect) contains(x, y int) bool {
+	return x >= r.left && x < r.right && y >= r.top && y < r.bottom
+}
+
+// intersect returns the cells shared by both rectangles
+// @parma other: the other rectangle
+// @return the intersection, which may be empty
+func (r canvasRect) intersect(other canvasRect) canvasRect {
+	return canvasRect{
+		left:   maxInt(r.left, other.left),
+		top:    maxInt(r.top, other.top),
+		right:  minInt(r.right, other.right),
+		bottom: minInt(r.bottom, other.bottom),
+	}
+}
+
+// inset shrinks the rectangle on all four sides
+// @parma size: number of cells removed from each side
+func (r canvasRect) inset(size int) canvasRect {
+	return canvasRect{left: r.left + size, top: r.top + size, right: r.right - size, bottom: r.bottom - size}
+}
+
+// bounds converts the rectangle to the exported CanvasBounds
+func (r canvasRect) bounds() CanvasBounds {
+	return CanvasBounds{X: r.left, Y: r.top, Width: r.width(), Height: r.height()}
+}
+
+// minInt auxiliary function, returns the smaller value
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt auxiliary function, returns the larger value
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// absInt auxiliary function, returns the absolute value
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,261 @@
+package tml
+
+// Scrollbar characters
+const (
+	scrollTrackRune = '│'
+	scrollThumbRune = '█'
+)
+
+// scrollState the scroll information of a node whose children are placed in a scrolled area that can be larger than the node
+type scrollState struct {
+	enabled        bool       //whether the last layout pass found an axis that scrolls
+	x              int        //horizontal offset of the content
+	y              int        //vertical offset of the content
+	contentWidth   int        //width of the content, 0 or Auto measures the children and the text
+	contentHeight  int        //height of the content, 0 or Auto measures the children and the text
+	showVertical   bool       //whether the last layout pass shows the vertical scrollbar
+	showHorizontal bool       //whether the last layout pass shows the horizontal scrollbar
+	noVertical     bool       //whether the vertical scrollbar is never shown, see ScrollView.SetScrollBars
+	noHorizontal   bool       //whether the horizontal scrollbar is never shown
+	viewport       canvasRect //the visible part of the content area, the content area without the scrollbars
+}
+
+// scrolls reports whether an overflow mode gives the axis a scroll viewport
+func scrolls(overflow uint8) bool {
+	return overflow == OverflowScroll || overflow == OverflowAuto
+}
+
+// resolveOverflow returns the overflow the node is placed with, an axis the style leaves OverflowHidden takes the overflow of the widget
+func (c *Canvas) resolveOverflow() CanvasOverflow {
+	overflow := c.style.Overflow
+	if overflow.Horizontal == OverflowHidden {
+		overflow.Horizontal = c.overflow.Horizontal
+	}
+	if overflow.Vertical == OverflowHidden {
+		overflow.Vertical = c.overflow.Vertical
+	}
+	return overflow
+}
+
+// layoutScroll computes the viewport and the scrolled content area of a node, the offsets are clamped to the content.
+// An axis that does not scroll keeps the size of the viewport
+// @parma canvas: the scrolling node
+// @return the viewport that the children are clipped to
+func layoutScroll(canvas *Canvas) canvasRect {
+	scroll := &canvas.scroll
+	overflow := canvas.resolveOverflow()
+	content := canvas.contentRect()
+	scroll.showVertical = overflow.Vertical == OverflowScroll && !scroll.noVertical
+	scroll.showHorizontal = overflow.Horizontal == OverflowScroll && !scroll.noHorizontal
+
+	viewport := content
+	width, height := 0, 0
+	for pass := 0; pass < 2; pass++ { // A scrollbar takes space from the other axis, so showing one may require the other
+		viewport = content
+		if scroll.showVertical {
+			viewport.right--
+		}
+		if scroll.showHorizontal {
+			viewport.bottom--
+		}
+		width, height = measureScrollContent(canvas, viewport)
+		scroll.showVertical = scroll.showVertical || (overflow.Vertical == OverflowAuto && height > viewport.height() && !scroll.noVertical)
+		scroll.showHorizontal = scroll.showHorizontal || (overflow.Horizontal == OverflowAuto && width > viewport.width() && !scroll.noHorizontal)
+	}
+
+	width = maxInt(width, viewport.width())
+	height = maxInt(height, viewport.height())
+	if !scrolls(overflow.Horizontal) {
+		width = viewport.width()
+	}
+	if !scrolls(overflow.Vertical) {
+		height = viewport.height()
+	}
+	scroll.x = maxInt(minInt(scroll.x, width-viewport.width()), 0)
+	scroll.y = maxInt(minInt(scroll.y, height-viewport.height()), 0)
+	scroll.viewport = viewport
+
+	canvas.childArea = canvasRect{left: viewport.left - scroll.x, top: viewport.top - scroll.y}
+	canvas.childArea.right = canvas.childArea.left + width
+	canvas.childArea.bottom = canvas.childArea.top + height
+	return viewport
+}
+
+// measureScrollContent measures the content of a scrolling node, explicit sizes are used as they are
+// @parma canvas: the scrolling node viewport: the visible area, relative sizes of the children are resolved against it
+// @return width, height
+func measureScrollContent(canvas *Canvas, viewport canvasRect) (int, int) {
+	scroll := canvas.scroll
+	width, height := scroll.contentWidth, scroll.contentHeight
+	if !scrolls(canvas.resolveOverflow().Horizontal) { // The text wraps at the viewport when the content cannot scroll sideways
+		width = viewport.width()
+	}
+	if width > 0 && width != Auto && height > 0 && height != Auto {
+		return width, height
+	}
+
+	measuredWidth, measuredHeight := 0, 0
+	if canvas.style.Layout != LayoutNone { // The items of a layout are measured the way SizeFit measures them
+		measuredWidth = maxInt(fitWidth(canvas, 0), viewport.width())
+		measuredHeight = fitHeight(canvas, measuredWidth, 0)
+	} else {
+		canvas.childArea = viewport // Children are measured from the top left corner of the content
+		for _, child := range layoutItems(canvas.children) {
+			child.getCanvas().layoutManaged = false
+			rect := confirmSquareBounds(child.getCanvas())
+			measuredWidth = maxInt(measuredWidth, rect.right-viewport.left)
+			measuredHeight = maxInt(measuredHeight, rect.bottom-viewport.top)
+		}
+	}
+
+	if width <= 0 || width == Auto {
+		width = measuredWidth
+	}
+	if measurer, ok := canvas.self.(contentMeasurer); ok { // Widgets measure their own content at the width of the viewport
+		contentWidth, contentHeight := measurer.measureContent(viewport.width())
+		if scroll.contentWidth <= 0 || scroll.contentWidth == Auto {
+			width = maxInt(width, contentWidth)
+		}
+		measuredHeight = maxInt(measuredHeight, contentHeight)
+	} else if textWidth := maxInt(width, viewport.width()); textWidth > 0 { // The text flows through the whole width of the content
+		measuredHeight = maxInt(measuredHeight, (canvas.spans.Len()+textWidth-1)/textWidth)
+	}
+	if height <= 0 || height == Auto {
+		height = measuredHeight
+	}
+	return width, height
+}
+
+// drawScrollBars paints the scrollbars chosen by the layout pass
+// @parma canvas: the scrolling node clip: the visible area of the node
+func drawScrollBars(canvas *Canvas, clip canvasRect) {
+	scroll := canvas.scroll
+	viewport := scroll.viewport
+	trackCell := styleCell(canvas.style, scrollTrackRune)
+	trackCell.attribute |= AttrDim
+	thumbCell := styleCell(canvas.style, scrollThumbRune)
+
+	if scroll.showVertical {
+		start, size := scrollThumb(viewport.height(), canvas.childArea.height(), scroll.y)
+		for i := 0; i < viewport.height(); i++ {
+			barCell := trackCell
+			if i >= start && i < start+size {
+				barCell = thumbCell
+			}
+			screenBuffer.set(viewport.right, viewport.top+i, barCell, clip)
+		}
+	}
+	if scroll.showHorizontal {
+		start, size := scrollThumb(viewport.width(), canvas.childArea.width(), scroll.x)
+		for i := 0; i < viewport.width(); i++ {
+			barCell := trackCell
+			barCell.char = '─'
+			if i >= start && i < start+size {
+				barCell = thumbCell
+			}
+			screenBuffer.set(viewport.left+i, viewport.bottom, barCell, clip)
+		}
+	}
+	if scroll.showVertical && scroll.showHorizontal {
+		screenBuffer.set(viewport.right, viewport.bottom, styleCell(canvas.style, ' '), clip)
+	}
+}
+
+// scrollThumb computes the thumb of a scrollbar
+// @parma view: size of the viewport content: size of the content offset: scroll offset
+// @return start and size of the thumb, relative to the track
+func scrollThumb(view, content, offset int) (int, int) {
+	if content <= view || view <= 0 {
+		return 0, view
+	}
+	size := maxInt(view*view/content, 1)
+	return offset * (view - size) / (content - view), size
+}
+
+// scrollNode scrolls a node and triggers OnScroll when the offsets change
+// @parma node: the scrolling node x: horizontal offset y: vertical offset
+func scrollNode(node Node, x, y int) {
+	if node.getCanvas().scrollTo(x, y) {
+		triggerEvent(node, OnScroll, node)
+		Render()
+	}
+}
+
+// wheelScroll scrolls the closest scrolling ancestor of a node with a mouse wheel report, Shift turns the vertical wheel into a horizontal one
+// @parma node: the node under the mouse mouse: the wheel report
+// @return whether a node was scrolled
+func wheelScroll(node Node, mouse MouseEvent) bool {
+	for ; node != nil; node, _ = node.GetParent() {
+		if node.isUnMount() {
+			return false
+		}
+		if _, ok := node.(*Modal); ok { // The nodes beneath a modal do not scroll
+			return false
+		}
+		canvas := node.getCanvas()
+		if !canvas.scroll.enabled {
+			continue
+		}
+		action := mouse.Action
+		if mouse.Modifier&ModShift != 0 && action <= MouseWheelDown {
+			action += MouseWheelLeft - MouseWheelUp
+		}
+		x, y := canvas.scroll.x, canvas.scroll.y
+		switch action {
+		case MouseWheelUp:
+			y -= ScrollWheelStep
+		case MouseWheelDown:
+			y += ScrollWheelStep
+		case MouseWheelLeft:
+			x -= ScrollWheelStep
+		case MouseWheelRight:
+			x += ScrollWheelStep
+		}
+		scrollNode(node, x, y)
+		return true
+	}
+	return false
+}
+
+// scrollTo moves the content of a scrolling node, the offsets are clamped to the content computed by the last layout pass
+// @parma canvas: the scrolling node x: horizontal offset y: vertical offset
+// @return whether the offsets changed
+func (c *Canvas) scrollTo(x, y int) bool {
+	ensureLayout()
+	x, y = maxInt(x, 0), maxInt(y, 0)
+	if c.displayed { // The content of a node that has not been placed yet is unknown, the next layout pass clamps the offsets
+		x = minInt(x, maxInt(c.childArea.width()-c.scroll.viewport.width(), 0))
+		y = minInt(y, maxInt(c.childArea.height()-c.scroll.viewport.height(), 0))
+	}
+	if x == c.scroll.x && y == c.scroll.y {
+		return false
+	}
+	c.scroll.x, c.scroll.y = x, y
+	invalidateLayout()
+	return true
+}
+
+// scrollIntoView computes the offsets that bring a descendant into the viewport with the least movement
+// @parma c: the scrolling node target: the descendant
+// @return horizontal offset, vertical offset
+func (c *Canvas) scrollIntoView(target *Canvas) (int, int) {
+	ensureLayout()
+	viewport := c.scroll.viewport
+	rect := target.rect
+	x, y := c.scroll.x, c.scroll.y
+	left, top := rect.left-c.childArea.left, rect.top-c.childArea.top
+
+	if left+rect.width() > x+viewport.width() {
+		x = left + rect.width() - viewport.width()
+	}
+	if left < x {
+		x = left
+	}
+	if top+rect.height() > y+viewport.height() {
+		y = top + rect.height() - viewport.height()
+	}
+	if top < y {
+		y = top
+	}
+	return x, y
+}
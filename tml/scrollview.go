@@ -0,0 +1,146 @@
+package tml
+
+import "errors"
+
+// ScrollView a viewport whose children and text are placed in a content area that can be larger than the node, it is a node
+// whose overflow is OverflowAuto on both axes unless its style sets another one, the content scrolls with the mouse wheel and
+// with the arrows, PgUp, PgDn, Home and End when it is selected
+type ScrollView struct {
+	Quadrilateral //inherited struct
+}
+
+// CreateScrollView Creates a scrollable viewport, its content is measured from its children and text until SetContentSize is called
+// @parma name: the name of the node, does not force uniqueness
+// @return the scroll view, loaded into each global repository before it returns
+func CreateScrollView(name string) *ScrollView {
+	element := new(ScrollView)
+	mountQuadrilateral(element, &element.Quadrilateral, ScrollViewTag, name)
+	element.overflow = CanvasOverflow{Horizontal: OverflowAuto, Vertical: OverflowAuto}
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		node.(*ScrollView).scrollByKey()
+	})
+
+	return element
+}
+
+// SetContentSize sets the size of the content, 0 or Auto measures it from the children and the text. The content is never smaller than the viewport
+// @parma width: width of the content height: height of the content
+func (sv *ScrollView) SetContentSize(width, height int) error {
+	if sv.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if sv.scroll.contentWidth != width || sv.scroll.contentHeight != height {
+		sv.scroll.contentWidth = width
+		sv.scroll.contentHeight = height
+		invalidateLayout()
+		Render()
+	}
+	return nil
+}
+
+// GetContentSize returns the size of the content computed by the layout pass
+// @return width, height
+func (sv *ScrollView) GetContentSize() (int, int, error) {
+	if sv.unMount {
+		return 0, 0, errors.New(OperatingEmptyNodeError)
+	}
+	ensureLayout()
+	return sv.childArea.width(), sv.childArea.height(), nil
+}
+
+// SetScrollBars chooses when the scrollbars are shown, the content scrolls on both axes whatever the scrollbars are
+// @parma vertical: mode of the vertical scrollbar horizontal: mode of the horizontal scrollbar, see ScrollBarAuto
+func (sv *ScrollView) SetScrollBars(vertical, horizontal uint8) error {
+	if sv.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	sv.overflow = CanvasOverflow{Horizontal: scrollBarOverflow(horizontal), Vertical: scrollBarOverflow(vertical)}
+	sv.scroll.noVertical = vertical == ScrollBarNone
+	sv.scroll.noHorizontal = horizontal == ScrollBarNone
+	invalidateLayout()
+	Render()
+	return nil
+}
+
+// scrollBarOverflow returns the overflow of an axis of a scroll view that shows its scrollbar in a mode
+// @parma mode: ScrollBarNone, ScrollBarAuto or ScrollBarAlways
+func scrollBarOverflow(mode uint8) uint8 {
+	if mode == ScrollBarAlways {
+		return OverflowScroll
+	}
+	return OverflowAuto
+}
+
+// SetScrollOffset scrolls the content to an offset, the offset is clamped to the content and OnScroll is triggered when it changes
+// @parma x: horizontal offset y: vertical offset
+func (sv *ScrollView) SetScrollOffset(x, y int) error {
+	if sv.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	scrollNode(sv, x, y)
+	return nil
+}
+
+// GetScrollOffset returns the offset of the content
+// @return horizontal offset, vertical offset
+func (sv *ScrollView) GetScrollOffset() (int, int, error) {
+	if sv.unMount {
+		return 0, 0, errors.New(OperatingEmptyNodeError)
+	}
+	ensureLayout()
+	return sv.scroll.x, sv.scroll.y, nil
+}
+
+// ScrollBy moves the content relative to the current offset
+// @parma dx: horizontal distance dy: vertical distance
+func (sv *ScrollView) ScrollBy(dx, dy int) error {
+	x, y, err := sv.GetScrollOffset()
+	if err != nil {
+		return err
+	}
+	return sv.SetScrollOffset(x+dx, y+dy)
+}
+
+// ScrollTo scrolls the least distance that brings a descendant into the viewport
+// @parma node: a descendant of the scroll view
+func (sv *ScrollView) ScrollTo(node Node) error {
+	if sv.unMount || node.isUnMount() {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	parent, _ := node.GetParent()
+	for parent != nil && parent != Node(sv) {
+		parent, _ = parent.GetParent()
+	}
+	if parent == nil {
+		return errors.New(NotDescendantError)
+	}
+	x, y := sv.scrollIntoView(node.getCanvas())
+	return sv.SetScrollOffset(x, y)
+}
+
+// scrollByKey scrolls the content with the key of the last keyboard event
+func (sv *ScrollView) scrollByKey() {
+	ensureLayout()
+	page := maxInt(sv.scroll.viewport.height()-1, 1)
+	switch sv.KeyEvent.Key {
+	case KeyArrowUp:
+		sv.ScrollBy(0, -1)
+	case KeyArrowDown:
+		sv.ScrollBy(0, 1)
+	case KeyArrowLeft:
+		sv.ScrollBy(-1, 0)
+	case KeyArrowRight:
+		sv.ScrollBy(1, 0)
+	case KeyPgup:
+		sv.ScrollBy(0, -page)
+	case KeyPgdn:
+		sv.ScrollBy(0, page)
+	case KeyHome:
+		sv.SetScrollOffset(0, 0)
+	case KeyEnd:
+		sv.SetScrollOffset(sv.scroll.x, sv.childArea.height())
+	default: // Other keys belong to the ancestors
+		forwardKey(sv)
+	}
+}
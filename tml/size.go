@@ -0,0 +1,235 @@
+package tml
+
+// contentMeasurer is implemented by the widgets whose content is not measured as a single line of text, it is used by SizeFit
+type contentMeasurer interface {
+	measureContent(width int) (int, int) //returns the natural width of the content and its height at width, width is not positive when it is unknown
+}
+
+// resolveVolume resolves the units and the constraints of the volume of a node
+// @parma canvas: the node reference: the area the node is placed in, percentages are relative to it
+// xStart: absolute x of the node yStart: absolute y of the node, the fill unit takes the space from them to the end of reference
+// @return width, height
+func resolveVolume(canvas *Canvas, reference canvasRect, xStart, yStart int) (int, int) {
+	volume := canvas.volume
+	border := 0
+	if canvas.style.BorderType != None {
+		border = 2
+	}
+
+	width := resolveLength(volume.Width, volume.WidthUnit, reference.width(), reference.right-xStart, canvas.style.AutoSize, func() int {
+		return fitWidth(canvas, border)
+	})
+	width = clampLength(width, volume.MinWidth, volume.MaxWidth)
+
+	height := resolveLength(volume.Height, volume.HeightUnit, reference.height(), reference.bottom-yStart, canvas.style.AutoSize, func() int {
+		return fitHeight(canvas, width, border)
+	})
+	height = clampLength(height, volume.MinHeight, volume.MaxHeight)
+
+	return width, height
+}
+
+// resolveLength resolves one axis of a volume
+// @parma length: value of the axis unit: unit of the axis available: size of the reference on the axis
+// remaining: space from the start of the node to the end of the reference autoSize: whether Auto inherits the reference fit: measures the content
+// @return the number of cells
+func resolveLength(length int, unit uint8, available, remaining int, autoSize bool, fit func() int) int {
+	switch unit {
+	case SizePercent:
+		return available * length / 100
+	case SizeFill:
+		return maxInt(remaining, 0)
+	case SizeFit: // The content wraps when it does not fit in the remaining space
+		return minInt(fit(), maxInt(remaining, 0))
+	}
+	if length == Auto {
+		if autoSize { // Adaptive size inherits from the parent
+			return available
+		}
+		return 0
+	}
+	return length
+}
+
+// clampLength applies the minimum and maximum of an axis, 0 means no constraint
+// @parma length: resolved length min: minimum max: maximum
+func clampLength(length, min, max int) int {
+	if max > 0 && length > max {
+		length = max
+	}
+	if min > 0 && length < min {
+		length = min
+	}
+	return length
+}
+
+// fitWidth measures the width of the content of a node, which is its text on a single line or the extent of its children
+// @parma canvas: the node border: cells taken by the border
+func fitWidth(canvas *Canvas, border int) int {
+	width := canvas.spans.Len()
+	if measurer, ok := canvas.self.(contentMeasurer); ok { // Widgets measure their own content
+		width, _ = measurer.measureContent(0)
+	}
+	items := layoutItems(canvas.children)
+	style := canvas.style
+	extent := 0
+	switch {
+	case style.Layout == LayoutFlex && flexRow(style.Flex):
+		for index, child := range items { // Items of a row are placed side by side, the gap only separates two items
+			childWidth, _ := measureChild(child.getCanvas(), 0)
+			if index > 0 {
+				extent += style.Flex.Gap
+			}
+			extent += childWidth
+		}
+	case style.Layout == LayoutGrid:
+		extent = fitGrid(style.Grid, items, true)
+	default:
+		for _, child := range items {
+			childCanvas := child.getCanvas()
+			childWidth, _ := measureChild(childCanvas, 0)
+			if style.Layout == LayoutNone {
+				childWidth += childCanvas.position.X
+			}
+			extent = maxInt(extent, childWidth)
+		}
+	}
+	return maxInt(width, extent) + border
+}
+
+// fitHeight measures the height of the content of a node, which is the lines its text wraps into or the extent of its children
+// @parma canvas: the node width: resolved width of the node border: cells taken by the border
+func fitHeight(canvas *Canvas, width int, border int) int {
+	height := 0
+	contentWidth := width - border
+	if measurer, ok := canvas.self.(contentMeasurer); ok {
+		_, height = measurer.measureContent(contentWidth)
+	} else if contentWidth > 0 {
+		height = (canvas.spans.Len() + contentWidth - 1) / contentWidth
+	}
+	items := layoutItems(canvas.children)
+	style := canvas.style
+	extent := 0
+	switch {
+	case style.Layout == LayoutFlex && !flexRow(style.Flex):
+		for index, child := range items { // Items of a column are placed one below the other, the gap only separates two items
+			_, childHeight := measureChild(child.getCanvas(), contentWidth)
+			if index > 0 {
+				extent += style.Flex.Gap
+			}
+			extent += childHeight
+		}
+	case style.Layout == LayoutGrid:
+		extent = fitGrid(style.Grid, items, false)
+	default:
+		for _, child := range items {
+			childCanvas := child.getCanvas()
+			_, childHeight := measureChild(childCanvas, contentWidth)
+			if style.Layout == LayoutNone {
+				childHeight += childCanvas.position.Y
+			}
+			extent = maxInt(extent, childHeight)
+		}
+	}
+	return maxInt(height, extent) + border
+}
+
+// measureChild measures the size a child asks for when its parent is fitted to its children, sizes relative to the parent are
+// not known yet and count as 0
+// @parma canvas: the child available: the width the child may wrap in, not positive when it is unknown
+// @return width, height
+func measureChild(canvas *Canvas, available int) (int, int) {
+	volume := canvas.volume
+	border := 0
+	if canvas.style.BorderType != None {
+		border = 2
+	}
+
+	width := 0
+	switch {
+	case volume.WidthUnit == SizeFit:
+		width = fitWidth(canvas, border)
+		if available > 0 { // The content wraps when it does not fit
+			width = minInt(width, available)
+		}
+	case volume.WidthUnit == SizeCell && volume.Width != Auto:
+		width = volume.Width
+	}
+	width = clampLength(width, volume.MinWidth, volume.MaxWidth)
+
+	height := 0
+	switch {
+	case volume.HeightUnit == SizeFit:
+		height = fitHeight(canvas, width, border)
+	case volume.HeightUnit == SizeCell && volume.Height != Auto:
+		height = volume.Height
+	}
+	height = clampLength(height, volume.MinHeight, volume.MaxHeight)
+	return width, height
+}
+
+// fitGrid measures a grid container from its items, the rows are measured with the items wrapped in the columns they span
+// @parma grid: tracks of the container items: the items in order column: whether the width is measured instead of the height
+// @return the sum of the tracks and the gaps between them
+func fitGrid(grid GridContainer, items NodeStack, column bool) int {
+	columns := grid.Columns
+	if len(columns) == 0 {
+		columns = []GridTrack{FractionTrack(1)}
+	}
+	entries := placeGridItems(items, len(columns))
+
+	columnSizes := fitGridTracks(columns, entries, func(entry gridEntry) (int, int, int) {
+		width, _ := measureChild(entry.node.getCanvas(), 0)
+		return entry.column, entry.columnSpan, width
+	})
+	if column {
+		return trackExtent(columnSizes, grid.ColumnGap)
+	}
+
+	rows := append([]GridTrack{}, grid.Rows...)
+	for _, entry := range entries { // Add implicit rows for the items placed below the defined rows
+		for len(rows) < entry.row+entry.rowSpan {
+			rows = append(rows, AutoTrack())
+		}
+	}
+	rowSizes := fitGridTracks(rows, entries, func(entry gridEntry) (int, int, int) {
+		_, height := measureChild(entry.node.getCanvas(), trackExtent(columnSizes[entry.column:entry.column+entry.columnSpan], grid.ColumnGap))
+		return entry.row, entry.rowSpan, height
+	})
+	return trackExtent(rowSizes, grid.RowGap)
+}
+
+// fitGridTracks measures the tracks of a grid container the way the layout resolves them, fixed tracks keep their size and the
+// other tracks are as large as the largest item that only occupies them
+// @parma tracks: the tracks entries: the placed items measure: returns the track, the span and the size of an item
+// @return the size of every track
+func fitGridTracks(tracks []GridTrack, entries []gridEntry, measure func(entry gridEntry) (int, int, int)) []int {
+	sizes := make([]int, len(tracks))
+	for index, track := range tracks {
+		if track.Type == TrackFixed {
+			sizes[index] = track.Size
+		}
+	}
+	for _, entry := range entries {
+		start, span, size := measure(entry)
+		if span == 1 && tracks[start].Type != TrackFixed {
+			sizes[start] = maxInt(sizes[start], size)
+		}
+	}
+	return sizes
+}
+
+// trackExtent returns the cells covered by consecutive tracks and the gaps between them
+// @parma sizes: size of every track gap: cells between two tracks
+func trackExtent(sizes []int, gap int) int {
+	extent := gap * maxInt(len(sizes)-1, 0)
+	for _, size := range sizes {
+		extent += size
+	}
+	return extent
+}
+
+// flexRow reports whether the items of a flex container are placed in a row
+func flexRow(flex FlexContainer) bool {
+	return flex.Direction == FlexRow || flex.Direction == FlexRowReverse
+}
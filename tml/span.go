@@ -0,0 +1,264 @@
+package tml
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TextSpan a piece of text rendered with its own style, unset fields inherit the style of the node
+type TextSpan struct {
+	Text            string        //text of the span
+	Color           Color         //text color, NoColor inherits the text color of the node
+	BackGroundColor Color         //background color, NoColor inherits the background color of the node
+	Attribute       TextAttribute //attributes added to the attributes of the node
+}
+
+// StyledText a sequence of spans that makes up the text of a node
+type StyledText []TextSpan
+
+// styledRune a single character of a StyledText and the span it belongs to
+type styledRune struct {
+	char rune
+	span int
+}
+
+// Markup constant
+const (
+	markupOpen      = '['
+	markupClose     = ']'
+	markupSeparator = ":"
+	markupReset     = "-"
+	markupPalette   = "@"
+)
+
+// markupColors the color names that can be used in markup
+var markupColors = map[string]Color{
+	"default":      DefaultColor,
+	"black":        BlackColor,
+	"red":          RedColor,
+	"green":        GreenColor,
+	"yellow":       YellowColor,
+	"blue":         BlueColor,
+	"purple":       PurpleColor,
+	"magenta":      PurpleColor,
+	"cyan":         CyanColor,
+	"white":        WhiteColor,
+	"gray":         BrightBlackColor,
+	"grey":         BrightBlackColor,
+	"brightblack":  BrightBlackColor,
+	"brightred":    BrightRedColor,
+	"brightgreen":  BrightGreenColor,
+	"brightyellow": BrightYellowColor,
+	"brightblue":   BrightBlueColor,
+	"brightpurple": BrightPurpleColor,
+	"brightcyan":   BrightCyanColor,
+	"brightwhite":  BrightWhiteColor,
+}
+
+// markupAttributes the attribute flags that can be used in markup
+var markupAttributes = map[rune]TextAttribute{
+	'b': AttrBold,
+	'd': AttrDim,
+	'i': AttrItalic,
+	'u': AttrUnderline,
+	'U': AttrDoubleUnderline,
+	'c': AttrCurlyUnderline,
+	'l': AttrBlink,
+	'r': AttrReverse,
+	's': AttrStrikethrough,
+}
+
+// PlainText creates a StyledText of a single span that inherits the style of the node
+// @parma text: text of the span
+// @return the final styled text
+func PlainText(text string) StyledText {
+	if text == "" {
+		return StyledText{}
+	}
+	return StyledText{{Text: text}}
+}
+
+// ParseMarkup builds a StyledText from markup. A tag has the form [color:background:attributes], every field is optional,
+// an empty field keeps the current value and - resets it to the style of the node, [-] resets everything and [[ outputs a [.
+// Colors are names such as red or brightblue, palette indexes prefixed with @ such as @208 or hex values such as #ff8800,
+// attributes are the flags b(bold) d(dim) i(italic) u(underline) U(double underline) c(curly underline) l(blink) r(reverse) s(strikethrough).
+// Brackets that do not form a valid tag are output as they are, e.g. [red::b]error[-] renders error in bold red
+// @parma markup: markup text
+// @return the final styled text
+func ParseMarkup(markup string) StyledText {
+	text := StyledText{}
+	current := TextSpan{}
+	buf := strings.Builder{}
+
+	flush := func() {
+		if buf.Len() > 0 {
+			current.Text = buf.String()
+			text = append(text, current)
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(markup); i++ {
+		if markup[i] != markupOpen {
+			buf.WriteByte(markup[i])
+			continue
+		}
+		if i+1 < len(markup) && markup[i+1] == markupOpen { // Escaped bracket
+			buf.WriteByte(markupOpen)
+			i++
+			continue
+		}
+		end := strings.IndexByte(markup[i+1:], markupClose)
+		if end < 0 {
+			buf.WriteByte(markup[i])
+			continue
+		}
+		span, ok := parseMarkupTag(markup[i+1:i+1+end], current)
+		if !ok {
+			buf.WriteByte(markup[i])
+			continue
+		}
+		flush()
+		current = span
+		i += end + 1
+	}
+	flush()
+
+	return text
+}
+
+// parseMarkupTag parses the content of a markup tag
+// @parma tag: content between the brackets current: the style before the tag
+// @return the style after the tag and whether the tag is valid
+func parseMarkupTag(tag string, current TextSpan) (TextSpan, bool) {
+	if tag == markupReset {
+		return TextSpan{}, true
+	}
+	fields := strings.Split(tag, markupSeparator)
+	if len(fields) > 3 || tag == "" {
+		return current, false
+	}
+
+	span := current
+	for index, field := range fields {
+		if field == "" {
+			continue
+		}
+		switch index {
+		case 0, 1:
+			color, ok := parseMarkupColor(field)
+			if !ok {
+				return current, false
+			}
+			if index == 0 {
+				span.Color = color
+			} else {
+				span.BackGroundColor = color
+			}
+		case 2:
+			if field == markupReset {
+				span.Attribute = AttrNone
+				continue
+			}
+			var attribute TextAttribute = AttrNone
+			for _, flag := range field {
+				flagAttribute, ok := markupAttributes[flag]
+				if !ok {
+					return current, false
+				}
+				attribute |= flagAttribute
+			}
+			span.Attribute = attribute
+		}
+	}
+	return span, true
+}
+
+// parseMarkupColor parses a color field of a markup tag
+// @parma field: color name, palette index prefixed with @ or hex value
+// @return the color and whether the field is valid
+func parseMarkupColor(field string) (Color, bool) {
+	if field == markupReset {
+		return NoColor, true
+	}
+	if color, ok := markupColors[strings.ToLower(field)]; ok {
+		return color, true
+	}
+	if strings.HasPrefix(field, "#") {
+		color, err := HexColor(field)
+		return color, err == nil
+	}
+	if strings.HasPrefix(field, markupPalette) { // Plain numbers are not colors so text such as x[1] stays as it is
+		if index, err := strconv.ParseUint(field[len(markupPalette):], 10, 8); err == nil {
+			return PaletteColor(uint8(index)), true
+		}
+	}
+	return NoColor, false
+}
+
+// String returns the text of all spans without styles
+func (st StyledText) String() string {
+	strBuff := strings.Builder{}
+	for _, span := range st {
+		strBuff.WriteString(span.Text)
+	}
+	return strBuff.String()
+}
+
+// Len returns the number of characters of the text
+func (st StyledText) Len() int {
+	length := 0
+	for _, span := range st {
+		length += len([]rune(span.Text))
+	}
+	return length
+}
+
+// runes flattens the spans into characters, every character remembers the span it belongs to
+func (st StyledText) runes() []styledRune {
+	runes := make([]styledRune, 0, len(st))
+	for index, span := range st {
+		for _, char := range span.Text {
+			runes = append(runes, styledRune{char: char, span: index})
+		}
+	}
+	return runes
+}
+
+// spanCell builds the cell of a character of a span, unset fields of the span inherit the style of the node
+// @parma style: style of the node span: the span to be rendered char: the character
+// @return the final cell
+func spanCell(style CanvasStyle, span TextSpan, char rune) cell {
+	c := styleCell(style, char)
+	if span.Color.IsSet() {
+		c.color = span.Color
+	}
+	if span.BackGroundColor.IsSet() {
+		c.backGroundColor = span.BackGroundColor
+	}
+	c.attribute |= span.Attribute
+	return c
+}
+
+// styleCell builds a cell with the text style of a node
+// @parma style: style of the node char: the character
+// @return the final cell
+func styleCell(style CanvasStyle, char rune) cell {
+	return cell{char: char, color: style.Color, backGroundColor: style.BackGroundColor, attribute: style.Attribute}
+}
+
+// styleSequence builds the VT100 sequence that resets all properties and then applies the colors and attributes
+// @parma color: text color backGroundColor: background color attribute: text attributes
+// @return the final VT100 style
+func styleSequence(color, backGroundColor Color, attribute TextAttribute) string {
+	strBuff := strings.Builder{}
+	strBuff.WriteString(closeAllProperties)
+	if color.IsSet() {
+		strBuff.WriteString(color.foreground())
+	}
+	if backGroundColor.IsSet() {
+		strBuff.WriteString(backGroundColor.background())
+	}
+	strBuff.WriteString(attribute.sgr())
+	return strBuff.String()
+}
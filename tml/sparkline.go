@@ -0,0 +1,103 @@
+package tml
+
+import (
+	"errors"
+	"math"
+)
+
+// Sparkline a compact chart of the latest values without axes, every column is a value drawn as a vertical bar with eighth blocks.
+// The newest value is on the right and the values that do not fit are left out on the left. A sparkline that is several rows high
+// stacks its blocks
+type Sparkline struct {
+	Quadrilateral           //inherited struct
+	values        []float64 //the values from the oldest to the newest
+	minValue      float64   //bottom of a fixed range
+	maxValue      float64   //top of a fixed range, the range is measured from the shown values when it is not larger than minValue
+}
+
+// CreateSparkline Creates an empty sparkline that is one row high and fills the width of its parent
+// @parma name: the name of the node, does not force uniqueness
+// @return the sparkline, loaded into each global repository before it returns
+func CreateSparkline(name string) *Sparkline {
+	element := new(Sparkline)
+	mountQuadrilateral(element, &element.Quadrilateral, SparklineTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFill, Height: 1}
+	element.style.Color = GreenColor
+	return element
+}
+
+// SetValues replaces the values
+// @parma values: the values from the oldest to the newest
+func (sl *Sparkline) SetValues(values []float64) error {
+	if sl.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	sl.values = append([]float64(nil), values...)
+	Render()
+	return nil
+}
+
+// AddValue appends the newest value, only the latest SparklineHistory values are kept
+// @parma value: the value
+func (sl *Sparkline) AddValue(value float64) error {
+	if sl.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	sl.values = append(sl.values, value)
+	if len(sl.values) > SparklineHistory {
+		sl.values = append(sl.values[:0], sl.values[len(sl.values)-SparklineHistory:]...)
+	}
+	Render()
+	return nil
+}
+
+// GetValues returns the values from the oldest to the newest
+func (sl *Sparkline) GetValues() ([]float64, error) {
+	if sl.unMount {
+		return sl.values, errors.New(OperatingEmptyNodeError)
+	}
+	return sl.values, nil
+}
+
+// SetRange fixes the values of the bottom and the top of the sparkline, values outside it are clamped
+// @parma min: value of the bottom max: value of the top, the range is measured from the shown values when it is not larger than min
+func (sl *Sparkline) SetRange(min, max float64) error {
+	if sl.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	sl.minValue, sl.maxValue = min, max
+	Render()
+	return nil
+}
+
+// sparklineRender paints the latest values of a sparkline, the lowest value keeps an eighth of a cell so every value is visible
+// @parma sl: pointer to the sparkline struct
+// @return the render result of the node
+func sparklineRender(sl *Sparkline) bool {
+	if sl.clip.empty() {
+		return false
+	}
+	style := sl.style
+	boxDrawing(&sl.Canvas, style)
+
+	content := sl.contentRect()
+	clip := content.intersect(sl.clip)
+	values := sl.values[maxInt(len(sl.values)-content.width(), 0):]
+	scale := autoScale([]ChartSeries{{Values: values}}, false, sl.minValue, sl.maxValue)
+	levels := content.height() * 8
+	x := content.right - len(values)
+	for index, value := range values {
+		level := 1 + int(math.Round(scale.fraction(value)*float64(levels-1)))
+		for row := 0; row < content.height(); row++ {
+			char := ' '
+			switch filled := level - row*8; {
+			case filled >= 8:
+				char = chartFullRune
+			case filled > 0:
+				char = chartEighthsUp[filled-1]
+			}
+			screenBuffer.set(x+index, content.bottom-1-row, styleCell(style, char), clip)
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package tml
+
+import (
+	"errors"
+	"time"
+)
+
+// Frame sets of a spinner, every frame is drawn in the same number of cells
+var (
+	SpinnerDots   = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}           //A Braille dot going around
+	SpinnerLine   = []string{"-", "\\", "|", "/"}                                        //An ASCII line turning
+	SpinnerCircle = []string{"◐", "◓", "◑", "◒"}                                         //A half filled circle turning
+	SpinnerArrow  = []string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}                     //An arrow turning
+	SpinnerBounce = []string{"⠁", "⠂", "⠄", "⠂"}                                         //A dot bouncing
+	SpinnerBlocks = []string{"▁", "▃", "▄", "▅", "▆", "▇", "█", "▇", "▆", "▅", "▄", "▃"} //A block growing and shrinking
+)
+
+// Spinner an indeterminate activity indicator, the frame is followed by the text of the node. A running spinner is advanced by the
+// renderer, so the application does not need its own goroutine
+type Spinner struct {
+	Quadrilateral               //inherited struct
+	frames        []string      //the frames in the order they are shown
+	frame         int           //index of the shown frame
+	interval      time.Duration //how long a frame is shown
+	lastFrame     time.Time     //when the shown frame was first shown
+	running       bool          //whether the spinner is animated
+}
+
+// CreateSpinner Creates a stopped spinner that shows SpinnerDots and is as large as its frame and its text
+// @parma name: the name of the node, does not force uniqueness label: the text shown after the frame
+// @return the spinner, loaded into each global repository before it returns
+func CreateSpinner(name string, label string) *Spinner {
+	element := new(Spinner)
+	mountQuadrilateral(element, &element.Quadrilateral, SpinnerTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFit, HeightUnit: SizeFit}
+	element.frames = SpinnerDots
+	element.interval = SpinnerInterval
+	element.text = label
+	element.spans = PlainText(label)
+	element.AddEventListener(OnRemove, func(node Node, origen Node) {
+		stopAnimation(node.(*Spinner))
+	})
+	return element
+}
+
+// Start animates the spinner from its current frame
+func (sp *Spinner) Start() error {
+	if sp.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if !sp.running {
+		sp.running = true
+		sp.lastFrame = time.Now()
+		startAnimation(sp)
+	}
+	return nil
+}
+
+// Stop stops the animation, the current frame stays on the screen
+func (sp *Spinner) Stop() error {
+	if sp.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if sp.running {
+		sp.running = false
+		stopAnimation(sp)
+	}
+	return nil
+}
+
+// IsRunning reports whether the spinner is animated
+func (sp *Spinner) IsRunning() (bool, error) {
+	if sp.unMount {
+		return sp.running, errors.New(OperatingEmptyNodeError)
+	}
+	return sp.running, nil
+}
+
+// SetFrames sets the frames of the spinner, such as SpinnerLine, the animation starts again from the first frame
+// @parma frames: the frames, ignored when empty
+func (sp *Spinner) SetFrames(frames []string) error {
+	if sp.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if len(frames) > 0 {
+		sp.frames = frames
+		sp.frame = 0
+		invalidateLayout()
+		Render()
+	}
+	return nil
+}
+
+// SetInterval sets how long a frame is shown, it is rounded up to AnimationTick by the renderer
+// @parma interval: the duration of a frame
+func (sp *Spinner) SetInterval(interval time.Duration) error {
+	if sp.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	sp.interval = interval
+	return nil
+}
+
+// due reports whether the current frame of a visible spinner was shown for the interval
+// @parma now: the time of the check
+func (sp *Spinner) due(now time.Time) bool {
+	return sp.running && sp.displayed && now.Sub(sp.lastFrame) >= sp.interval
+}
+
+// tick shows the next frame when the current one was shown for the interval
+// @parma now: the time of the frame
+func (sp *Spinner) tick(now time.Time) {
+	if !sp.running || now.Sub(sp.lastFrame) < sp.interval {
+		return
+	}
+	sp.lastFrame = now
+	sp.frame = (sp.frame + 1) % len(sp.frames)
+}
+
+// frameWidth returns the number of cells of the widest frame
+func (sp *Spinner) frameWidth() int {
+	width := 0
+	for _, frame := range sp.frames {
+		width = maxInt(width, len([]rune(frame)))
+	}
+	return width
+}
+
+// measureContent measures the frame and the text of a spinner on a single line
+// @parma width: the width of the node, unused because the spinner does not wrap
+// @return the width of the frame, a space and the text, and one line
+func (sp *Spinner) measureContent(width int) (int, int) {
+	textWidth := sp.spans.Len()
+	if textWidth > 0 {
+		textWidth++ // A space between the frame and the text
+	}
+	return sp.frameWidth() + textWidth, 1
+}
+
+// spinnerRender paints the frame of a spinner followed by its text
+// @parma sp: pointer to the spinner struct
+// @return the render result of the node
+func spinnerRender(sp *Spinner) bool {
+	if sp.clip.empty() {
+		return false
+	}
+	style := sp.style
+	boxDrawing(&sp.Canvas, style)
+
+	content := sp.contentRect()
+	clip := content.intersect(sp.clip)
+	for column, char := range []rune(sp.frames[sp.frame%len(sp.frames)]) {
+		screenBuffer.set(content.left+column, content.top, styleCell(style, char), clip)
+	}
+	if !style.ShowText {
+		return true
+	}
+	x := content.left + sp.frameWidth() + 1
+	for column, labelRune := range sp.spans.runes() {
+		screenBuffer.set(x+column, content.top, spanCell(style, sp.spans[labelRune.span], labelRune.char), clip)
+	}
+	return true
+}
@@ -0,0 +1,447 @@
+package tml
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// Sort indicator characters of the header
+const (
+	sortAscendingRune  = '▲'
+	sortDescendingRune = '▼'
+	columnSeparator    = '│'
+)
+
+// TableColumn describes a column of a Table
+type TableColumn struct {
+	Title string //text of the header
+	Width int    //width in cells, 0 lets the column share the free width by Flex
+	Flex  int    //weight of the column in the free width when Width is 0, a column is never narrower than its title
+	Align uint8  //alignment of the cells, AlignStart, AlignCenter or AlignEnd
+}
+
+// TableSource the rows of a Table, the table only asks for the cells of the rows it shows so a source may hold any number of rows
+type TableSource interface {
+	Len() int                            //number of rows
+	Cell(row int, column int) StyledText //the text of a cell, spans with their own colors style the cell
+}
+
+// TableSorter is implemented by the sources that can be sorted, the table sorts them when a header is clicked or SortBy is called
+type TableSorter interface {
+	Sort(column int, descending bool, row int) int //reorders the rows by the keys of a column and returns the new index of the row, -1 when row is outside the rows
+}
+
+// StringTable a TableSource made of rows of plain strings, it sorts numbers by value and the rest as strings
+type StringTable [][]string
+
+// Len returns the number of rows
+func (st StringTable) Len() int {
+	return len(st)
+}
+
+// Cell returns a string as plain text, missing cells are empty
+func (st StringTable) Cell(row int, column int) StyledText {
+	if column >= len(st[row]) {
+		return StyledText{}
+	}
+	return PlainText(st[row][column])
+}
+
+// Sort reorders the rows by a column, equal keys keep their order
+// @parma column: index of the column  descending: whether the rows are sorted in descending order  row: index of a row to follow
+// @return the index of the followed row after sorting, -1 when row is outside the rows
+func (st StringTable) Sort(column int, descending bool, row int) int {
+	key := func(row []string) string {
+		if column < len(row) {
+			return row[column]
+		}
+		return ""
+	}
+	order := make([]int, len(st)) // order[i] is the old index of the row that moves to i
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, k int) bool {
+		if descending {
+			return lessKey(key(st[order[k]]), key(st[order[i]]))
+		}
+		return lessKey(key(st[order[i]]), key(st[order[k]]))
+	})
+
+	rows := append(StringTable{}, st...)
+	moved := -1
+	for i, old := range order {
+		st[i] = rows[old]
+		if old == row {
+			moved = i
+		}
+	}
+	return moved
+}
+
+// lessKey compares two sort keys, numbers are compared by value and come before the other keys, which are compared as strings
+func lessKey(a, b string) bool {
+	numberA, errA := strconv.ParseFloat(a, 64)
+	numberB, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return numberA < numberB
+	}
+	if (errA == nil) != (errB == nil) {
+		return errA == nil
+	}
+	return a < b
+}
+
+// Table a grid of rows under a fixed header, only the visible rows are drawn. The rows scroll vertically below the header and the
+// columns scroll sideways when they are wider than the table. The selected row is moved with the arrows, PgUp, PgDn, Home, End and the mouse,
+// Left and Right scroll sideways and clicking a header sorts by its column. OnSelectItem is triggered when the selected row changes and OnActivate by Enter
+type Table struct {
+	Quadrilateral               //inherited struct
+	columns       []TableColumn //the columns
+	source        TableSource   //the rows
+	cursor        int           //index of the selected row
+	sortColumn    int           //index of the column the rows are sorted by, -1 when they are not sorted
+	descending    bool          //whether the rows are sorted in descending order
+}
+
+// CreateTable Creates a table of 40 by 10 cells without columns
+// @parma name: the name of the node, does not force uniqueness
+// @return the table, loaded into each global repository before it returns
+func CreateTable(name string) *Table {
+	element := new(Table)
+	mountQuadrilateral(element, &element.Quadrilateral, TableTag, name)
+	element.volume = CanvasVolume{Width: 40, Height: 10}
+	element.overflow = CanvasOverflow{Horizontal: OverflowAuto, Vertical: OverflowAuto}
+	element.source = StringTable{}
+	element.sortColumn = -1
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		table := node.(*Table)
+		if !table.navigateByKey() {
+			forwardKey(table)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*Table).selectByMouse()
+	})
+
+	return element
+}
+
+// SetColumns sets the columns
+// @parma columns: the columns from left to right
+func (t *Table) SetColumns(columns ...TableColumn) error {
+	if t.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	t.columns = append([]TableColumn{}, columns...)
+	if t.sortColumn >= len(columns) {
+		t.sortColumn = -1
+	}
+	invalidateLayout()
+	Render()
+	return nil
+}
+
+// GetColumns returns the columns
+func (t *Table) GetColumns() ([]TableColumn, error) {
+	if t.unMount {
+		return t.columns, errors.New(OperatingEmptyNodeError)
+	}
+	return t.columns, nil
+}
+
+// SetSource sets the rows, the first row is selected and the rows are sorted again when a sort column is set
+// @parma source: the rows, nil empties the table
+func (t *Table) SetSource(source TableSource) error {
+	if t.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if source == nil {
+		source = StringTable{}
+	}
+	t.source = source
+	t.cursor = 0
+	t.scroll.y = 0
+	if sorter, ok := source.(TableSorter); ok && t.sortColumn >= 0 {
+		sorter.Sort(t.sortColumn, t.descending, -1)
+	}
+	return t.Refresh()
+}
+
+// Refresh draws the table again after the rows of the source changed, the selected row is kept inside the rows
+func (t *Table) Refresh() error {
+	if t.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	t.cursor = maxInt(minInt(t.cursor, t.source.Len()-1), 0)
+	invalidateLayout()
+	Render()
+	return nil
+}
+
+// SortBy sorts the rows by a column when the source implements TableSorter, the selection follows the selected row to its new index
+// @parma column: index of the column descending: whether the rows are sorted in descending order
+func (t *Table) SortBy(column int, descending bool) error {
+	if t.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	sorter, ok := t.source.(TableSorter)
+	if !ok || column < 0 || column >= len(t.columns) {
+		return nil
+	}
+	t.sortColumn, t.descending = column, descending
+	if row := sorter.Sort(column, descending, t.cursor); row >= 0 {
+		t.cursor = row
+		t.scrollToCursor()
+	}
+	Render()
+	return nil
+}
+
+// GetSort returns the column the rows are sorted by, -1 when they are not sorted, and whether the order is descending
+func (t *Table) GetSort() (int, bool, error) {
+	if t.unMount {
+		return t.sortColumn, t.descending, errors.New(OperatingEmptyNodeError)
+	}
+	return t.sortColumn, t.descending, nil
+}
+
+// SetCursor selects a row and scrolls it into view
+// @parma row: index of the row, it is clamped to the rows
+func (t *Table) SetCursor(row int) error {
+	if t.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	t.moveCursor(row)
+	return nil
+}
+
+// GetCursor returns the index of the selected row, -1 when the table is empty
+func (t *Table) GetCursor() (int, error) {
+	cursor := t.cursor
+	if t.source.Len() == 0 {
+		cursor = -1
+	}
+	if t.unMount {
+		return cursor, errors.New(OperatingEmptyNodeError)
+	}
+	return cursor, nil
+}
+
+// moveCursor selects a row and triggers OnSelectItem when the selected row changes
+// @parma row: index of the row, it is clamped to the rows
+func (t *Table) moveCursor(row int) {
+	count := t.source.Len()
+	if count == 0 {
+		return
+	}
+	row = maxInt(minInt(row, count-1), 0)
+	changed := row != t.cursor
+	t.cursor = row
+	t.scrollToCursor()
+	Render()
+	if changed {
+		triggerEvent(t, OnSelectItem, t)
+	}
+}
+
+// navigateByKey moves the selected row and scrolls with the key of the last keyboard event
+// @return whether the key was used
+func (t *Table) navigateByKey() bool {
+	page := maxInt(t.scroll.viewport.height()-2, 1)
+	switch t.KeyEvent.Key {
+	case KeyArrowUp:
+		t.moveCursor(t.cursor - 1)
+	case KeyArrowDown:
+		t.moveCursor(t.cursor + 1)
+	case KeyPgup:
+		t.moveCursor(t.cursor - page)
+	case KeyPgdn:
+		t.moveCursor(t.cursor + page)
+	case KeyHome:
+		t.moveCursor(0)
+	case KeyEnd:
+		t.moveCursor(t.source.Len() - 1)
+	case KeyArrowLeft:
+		scrollNode(t, t.scroll.x-ScrollWheelStep, t.scroll.y)
+	case KeyArrowRight:
+		scrollNode(t, t.scroll.x+ScrollWheelStep, t.scroll.y)
+	case KeyEnter:
+		if t.source.Len() > 0 {
+			triggerEvent(t, OnActivate, t)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// selectByMouse selects the row under the left button, a click on a header sorts by its column and a second click reverses the order
+func (t *Table) selectByMouse() {
+	if t.mouse.Button != MouseLeft || t.mouse.Action != MousePress {
+		return
+	}
+	ensureLayout()
+	viewport := t.scroll.viewport
+	if t.mouse.Y == viewport.top {
+		x := viewport.left - t.scroll.x
+		for column, width := range t.columnWidths(viewport.width()) {
+			if t.mouse.X >= x && t.mouse.X < x+width {
+				t.SortBy(column, column == t.sortColumn && !t.descending)
+				return
+			}
+			x += width + 1
+		}
+		return
+	}
+	row := t.scroll.y + t.mouse.Y - viewport.top - 1
+	if row >= 0 && row < t.source.Len() {
+		t.moveCursor(row)
+	}
+}
+
+// scrollToCursor scrolls the least distance that brings the selected row below the header
+func (t *Table) scrollToCursor() {
+	ensureLayout()
+	if !t.displayed {
+		return
+	}
+	height := t.scroll.viewport.height() - 1
+	y := t.scroll.y
+	if t.cursor >= y+height {
+		y = t.cursor - height + 1
+	}
+	if t.cursor < y {
+		y = t.cursor
+	}
+	scrollNode(t, t.scroll.x, y)
+}
+
+// columnWidths resolves the widths of the columns, the free width is shared by the flexible columns
+// @parma available: the width of the viewport
+// @return the width of every column, the columns are separated by one cell
+func (t *Table) columnWidths(available int) []int {
+	widths := make([]int, len(t.columns))
+	used := maxInt(len(t.columns)-1, 0)
+	totalFlex := 0
+	for index, column := range t.columns {
+		if column.Width > 0 {
+			widths[index] = column.Width
+		} else {
+			widths[index] = len([]rune(column.Title)) + 1 // Room for the sort indicator
+			totalFlex += maxInt(column.Flex, 1)
+		}
+		used += widths[index]
+	}
+	free := available - used
+	if free <= 0 || totalFlex == 0 {
+		return widths
+	}
+	shared := 0
+	for index, column := range t.columns {
+		if column.Width > 0 {
+			continue
+		}
+		extra := free * maxInt(column.Flex, 1) / totalFlex
+		widths[index] += extra
+		shared += extra
+	}
+	for index := len(t.columns) - 1; index >= 0 && shared < free; index-- { // The cells left by the rounding go to the last flexible column
+		if t.columns[index].Width == 0 {
+			widths[index] += free - shared
+			break
+		}
+	}
+	return widths
+}
+
+// measureContent measures the columns and the rows of a table
+// @parma width: the width of the viewport
+// @return the width of the columns and the number of rows with the header
+func (t *Table) measureContent(width int) (int, int) {
+	total := 0
+	for _, columnWidth := range t.columnWidths(width) {
+		total += columnWidth + 1
+	}
+	return maxInt(total-1, 0), t.source.Len() + 1
+}
+
+// drawCell paints a text aligned in a cell, the text is cut at the width of the cell
+// @parma text: the text x: the left of the cell y: the row width: the width of the cell align: the alignment style: the style of the row clip: the visible area
+func drawCell(text StyledText, x, y, width int, align uint8, style CanvasStyle, clip canvasRect) {
+	runes := text.runes()
+	if len(runes) > width {
+		runes = runes[:width]
+	}
+	switch align {
+	case AlignCenter:
+		x += (width - len(runes)) / 2
+	case AlignEnd:
+		x += width - len(runes)
+	}
+	for index, textRune := range runes {
+		screenBuffer.set(x+index, y, spanCell(style, text[textRune.span], textRune.char), clip)
+	}
+}
+
+// tableRender paints the header and the visible rows of a table, the selected row is reversed
+// @parma t: pointer to the table struct
+// @return the render result of the node
+func tableRender(t *Table) bool {
+	if t.clip.empty() {
+		return false
+	}
+	style := t.style
+	boxDrawing(&t.Canvas, style)
+	drawScrollBars(&t.Canvas, t.clip)
+
+	viewport := t.scroll.viewport
+	widths := t.columnWidths(viewport.width())
+	separatorCell := styleCell(style, columnSeparator)
+	separatorCell.attribute |= AttrDim
+
+	headerStyle := style
+	headerStyle.Attribute |= AttrBold | AttrUnderline
+	x := viewport.left - t.scroll.x
+	for column, width := range widths {
+		for cellX := x; cellX < x+width; cellX++ {
+			screenBuffer.set(cellX, viewport.top, styleCell(headerStyle, ' '), t.childClip)
+		}
+		title := []rune(t.columns[column].Title)
+		if column == t.sortColumn {
+			if t.descending {
+				title = append(title, sortDescendingRune)
+			} else {
+				title = append(title, sortAscendingRune)
+			}
+		}
+		drawCell(PlainText(string(title)), x, viewport.top, width, t.columns[column].Align, headerStyle, t.childClip)
+		if column < len(widths)-1 {
+			screenBuffer.set(x+width, viewport.top, separatorCell, t.childClip)
+		}
+		x += width + 1
+	}
+
+	count := t.source.Len()
+	for row := t.scroll.y; row < count && row < t.scroll.y+viewport.height()-1; row++ {
+		y := viewport.top + 1 + row - t.scroll.y
+		rowStyle := style
+		if row == t.cursor {
+			rowStyle.Attribute |= AttrReverse
+			for cellX := viewport.left; cellX < viewport.right; cellX++ {
+				screenBuffer.set(cellX, y, styleCell(rowStyle, ' '), t.childClip)
+			}
+		}
+		x := viewport.left - t.scroll.x
+		for column, width := range widths {
+			drawCell(t.source.Cell(row, column), x, y, width, t.columns[column].Align, rowStyle, t.childClip)
+			if column < len(widths)-1 {
+				screenBuffer.set(x+width, y, separatorCell, t.childClip)
+			}
+			x += width + 1
+		}
+	}
+	return true
+}
@@ -0,0 +1,358 @@
+package tml
+
+import "errors"
+
+// Tab bar characters
+const (
+	tabCloseRune     = '×'
+	tabSeparatorRune = '│'
+)
+
+// tabEntry a tab of a Tabs node
+type tabEntry struct {
+	title    string      //text of the tab in the bar
+	content  Node        //the content, nil until a lazy tab is first shown
+	create   func() Node //creates the content of a lazy tab the first time it is shown
+	closable bool        //whether the tab shows a close button
+}
+
+// tabSpan the cells of a tab in the bar, relative to the left of the bar
+type tabSpan struct {
+	start int //first cell of the tab
+	end   int //cell after the tab
+	close int //cell of the close button, -1 when the tab is not closable
+}
+
+// Tabs a container that shows one content child at a time under a bar of tab titles, the contents are stretched below the bar.
+// A tab is chosen by clicking its title, with Left and Right while the tabs are selected, with Ctrl+PgUp and Ctrl+PgDn or with Alt+1 to Alt+9 from
+// anywhere inside the tabs. Lazy tabs create their content the first time they are shown, closable tabs are closed by their close button or Delete.
+// OnTabChange is triggered when the active tab changes, the content of the new tab is the source
+type Tabs struct {
+	Quadrilateral            //inherited struct
+	tabs          []tabEntry //the tabs from left to right
+	active        int        //index of the shown tab, -1 when there is no tab
+}
+
+// CreateTabs Creates an empty tabs container that fills the remaining space of its parent
+// @parma name: the name of the node, does not force uniqueness
+// @return the tabs, loaded into each global repository before it returns
+func CreateTabs(name string) *Tabs {
+	element := new(Tabs)
+	mountQuadrilateral(element, &element.Quadrilateral, TabsTag, name)
+	element.volume = CanvasVolume{WidthUnit: SizeFill, HeightUnit: SizeFill}
+	element.active = -1
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		tabs := node.(*Tabs)
+		if !tabs.switchByKey(origen) {
+			forwardKey(tabs)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*Tabs).switchByMouse()
+	})
+
+	return element
+}
+
+// AddTab adds a tab whose content is inserted now, the first tab becomes the active tab
+// @parma title: text of the tab content: the content, nil for an empty tab
+// @return index of the tab
+func (ts *Tabs) AddTab(title string, content Node) (int, error) {
+	if ts.unMount || (content != nil && content.isUnMount()) {
+		return -1, errors.New(OperatingEmptyNodeError)
+	}
+	ts.tabs = append(ts.tabs, tabEntry{title: title})
+	index := len(ts.tabs) - 1
+	ts.mountContent(index, content)
+	if ts.active < 0 {
+		ts.SetActiveTab(index)
+	}
+	Render()
+	return index, nil
+}
+
+// AddLazyTab adds a tab whose content is created the first time the tab is shown
+// @parma title: text of the tab create: creates the content
+// @return index of the tab
+func (ts *Tabs) AddLazyTab(title string, create func() Node) (int, error) {
+	if ts.unMount {
+		return -1, errors.New(OperatingEmptyNodeError)
+	}
+	ts.tabs = append(ts.tabs, tabEntry{title: title, create: create})
+	index := len(ts.tabs) - 1
+	if ts.active < 0 {
+		ts.SetActiveTab(index)
+	}
+	Render()
+	return index, nil
+}
+
+// SetTabClosable sets whether a tab shows a close button
+// @parma index: index of the tab closable: whether the tab can be closed
+func (ts *Tabs) SetTabClosable(index int, closable bool) error {
+	if ts.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if index >= 0 && index < len(ts.tabs) {
+		ts.tabs[index].closable = closable
+		Render()
+	}
+	return nil
+}
+
+// SetTabTitle sets the text of a tab
+// @parma index: index of the tab title: the text
+func (ts *Tabs) SetTabTitle(index int, title string) error {
+	if ts.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if index >= 0 && index < len(ts.tabs) {
+		ts.tabs[index].title = title
+		Render()
+	}
+	return nil
+}
+
+// GetTabCount returns the number of tabs
+func (ts *Tabs) GetTabCount() (int, error) {
+	if ts.unMount {
+		return len(ts.tabs), errors.New(OperatingEmptyNodeError)
+	}
+	return len(ts.tabs), nil
+}
+
+// GetTabContent returns the content of a tab, nil when a lazy tab has not been shown yet
+// @parma index: index of the tab
+func (ts *Tabs) GetTabContent(index int) (Node, error) {
+	if ts.unMount {
+		return nil, errors.New(OperatingEmptyNodeError)
+	}
+	if index < 0 || index >= len(ts.tabs) {
+		return nil, nil
+	}
+	return ts.tabs[index].content, nil
+}
+
+// GetActiveTab returns the index of the shown tab, -1 when there is no tab
+func (ts *Tabs) GetActiveTab() (int, error) {
+	if ts.unMount {
+		return ts.active, errors.New(OperatingEmptyNodeError)
+	}
+	return ts.active, nil
+}
+
+// SetActiveTab shows a tab, the content of a lazy tab is created first. When the selected node is inside the tabs,
+// the selection moves to the new content if it listens to keys or to the tabs otherwise
+// @parma index: index of the tab
+func (ts *Tabs) SetActiveTab(index int) error {
+	if ts.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if index < 0 || index >= len(ts.tabs) || index == ts.active {
+		return nil
+	}
+	focused := ts.containsSelection()
+	if ts.active >= 0 && ts.tabs[ts.active].content != nil {
+		setDisplay(ts.tabs[ts.active].content, false)
+	}
+	ts.active = index
+	tab := &ts.tabs[index]
+	if tab.content == nil && tab.create != nil { // Lazy contents are mounted when they are first shown
+		ts.mountContent(index, tab.create())
+		tab.create = nil
+	}
+	if tab.content != nil {
+		setDisplay(tab.content, true)
+	}
+	if focused {
+		if tab.content != nil && hasEvent(tab.content, OnKeyBord) {
+			Select(tab.content)
+		} else {
+			Select(ts)
+		}
+	}
+	Render()
+	triggerEvent(ts, OnTabChange, tab.content)
+	return nil
+}
+
+// CloseTab removes a tab and its content, the tab on the right of a closed active tab becomes active
+// @parma index: index of the tab
+func (ts *Tabs) CloseTab(index int) error {
+	if ts.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if index < 0 || index >= len(ts.tabs) {
+		return nil
+	}
+	focused := ts.containsSelection()
+	content := ts.tabs[index].content
+	ts.tabs = append(ts.tabs[:index], ts.tabs[index+1:]...)
+	if content != nil && !content.isUnMount() {
+		content.Remove()
+	}
+
+	switch {
+	case index < ts.active:
+		ts.active--
+	case index == ts.active:
+		ts.active = -1
+		next := minInt(index, len(ts.tabs)-1)
+		if focused {
+			Select(ts) // The selection leaves the removed content before the next tab takes it
+		}
+		if next >= 0 {
+			ts.SetActiveTab(next)
+		} else {
+			triggerEvent(ts, OnTabChange, nil)
+		}
+	}
+	Render()
+	return nil
+}
+
+// mountContent inserts the content of a tab, it is stretched below the bar and hidden until its tab is shown
+// @parma index: index of the tab content: the content
+func (ts *Tabs) mountContent(index int, content Node) {
+	if content == nil {
+		return
+	}
+	ts.tabs[index].content = content
+	position, _ := content.GetPosition()
+	position.X, position.Y = 0, 1
+	content.SetPosition(position, CanvasAnchor{Horizontal: AnchorStretch, Vertical: AnchorStretch})
+	setDisplay(content, index == ts.active)
+	ts.Insert(content)
+}
+
+// setDisplay shows or hides a node
+func setDisplay(node Node, display bool) {
+	style, _ := node.GetStyle()
+	if style.Display != display {
+		style.Display = display
+		node.SetStyle(style)
+	}
+}
+
+// containsSelection reports whether the selected node is the tabs or one of its descendants
+func (ts *Tabs) containsSelection() bool {
+	for node := SelectNode; node != nil; node, _ = node.GetParent() {
+		if node == Node(ts) {
+			return true
+		}
+	}
+	return false
+}
+
+// switchByKey chooses a tab with the key of the last keyboard event, Left, Right and Delete are only used while the tabs are selected
+// @parma origen: the node that received the key first
+// @return whether the key was used
+func (ts *Tabs) switchByKey(origen Node) bool {
+	event := ts.KeyEvent
+	direct := origen == Node(ts)
+	switch {
+	case event.Key == KeyEsc && event.Rune >= '1' && event.Rune <= '9': // Alt+digit
+		ts.SetActiveTab(int(event.Rune - '1'))
+	case event.Key == KeyPgup && KeyModifier&ModCtrl != 0, direct && event.Key == KeyArrowLeft:
+		ts.SetActiveTab(maxInt(ts.active-1, 0))
+	case event.Key == KeyPgdn && KeyModifier&ModCtrl != 0, direct && event.Key == KeyArrowRight:
+		ts.SetActiveTab(ts.active + 1)
+	case direct && (event.Key == KeyEnter || event.Key == KeyArrowDown):
+		if ts.active < 0 || ts.tabs[ts.active].content == nil {
+			return false
+		}
+		Select(ts.tabs[ts.active].content)
+	case direct && event.Key == KeyDelete:
+		if ts.active < 0 || !ts.tabs[ts.active].closable {
+			return false
+		}
+		ts.CloseTab(ts.active)
+	default:
+		return false
+	}
+	return true
+}
+
+// switchByMouse shows the tab under the left button or closes it when its close button is clicked
+func (ts *Tabs) switchByMouse() {
+	if ts.mouse.Button != MouseLeft || ts.mouse.Action != MousePress {
+		return
+	}
+	ensureLayout()
+	bar := ts.contentRect()
+	if ts.mouse.Y != bar.top {
+		return
+	}
+	x := ts.mouse.X - bar.left + ts.barOffset(bar.width())
+	for index, span := range ts.tabSpans() {
+		if x == span.close {
+			ts.CloseTab(index)
+			return
+		}
+		if x >= span.start && x < span.end {
+			ts.SetActiveTab(index)
+			return
+		}
+	}
+}
+
+// tabSpans places the tabs in the bar
+func (ts *Tabs) tabSpans() []tabSpan {
+	spans := make([]tabSpan, len(ts.tabs))
+	x := 0
+	for index, tab := range ts.tabs {
+		span := tabSpan{start: x, close: -1}
+		x += len([]rune(tab.title)) + 2 // One cell of padding on each side
+		if tab.closable {
+			span.close = x
+			x += 2
+		}
+		span.end = x
+		spans[index] = span
+		x++ // Separator
+	}
+	return spans
+}
+
+// barOffset returns the number of cells the bar is scrolled by to keep the active tab visible
+// @parma width: the width of the bar
+func (ts *Tabs) barOffset(width int) int {
+	if ts.active < 0 {
+		return 0
+	}
+	span := ts.tabSpans()[ts.active]
+	return maxInt(span.end-width, 0)
+}
+
+// tabsRender paints the tabs and their bar, the active tab is reversed
+// @parma ts: pointer to the tabs struct
+// @return the render result of the node
+func tabsRender(ts *Tabs) bool {
+	if ts.clip.empty() {
+		return false
+	}
+	style := ts.style
+	boxDrawing(&ts.Canvas, style)
+
+	bar := ts.contentRect()
+	clip := bar.intersect(ts.clip)
+	offset := ts.barOffset(bar.width())
+	separatorCell := styleCell(style, tabSeparatorRune)
+	separatorCell.attribute |= AttrDim
+	for index, span := range ts.tabSpans() {
+		tabStyle := style
+		if index == ts.active {
+			tabStyle.Attribute |= AttrReverse | AttrBold
+		}
+		label := []rune(" " + ts.tabs[index].title + " ")
+		if ts.tabs[index].closable {
+			label = append(label, tabCloseRune, ' ')
+		}
+		for column, char := range label {
+			screenBuffer.set(bar.left+span.start+column-offset, bar.top, styleCell(tabStyle, char), clip)
+		}
+		screenBuffer.set(bar.left+span.end-offset, bar.top, separatorCell, clip)
+	}
+	return true
+}
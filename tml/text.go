@@ -0,0 +1,142 @@
+package tml
+
+import "errors"
+
+// Text a lightweight node that only paints its characters, it sizes itself to its content, wraps its lines and
+// keeps the background of the nodes below it unless a background color is set. Text nodes have no border
+type Text struct {
+	Quadrilateral       //inherited struct
+	wrap          uint8 //how the lines are wrapped, see WrapWord
+}
+
+// CreateText Creates a text node that is as large as its text
+// @parma name: the name of the node, does not force uniqueness
+// @return the text node, loaded into each global repository before it returns
+func CreateText(name string) *Text {
+	element := new(Text)
+	mountQuadrilateral(element, &element.Quadrilateral, TextTag, name)
+	element.style.BackGroundColor = NoColor // Transparent by default
+	element.volume = CanvasVolume{WidthUnit: SizeFit, HeightUnit: SizeFit}
+	element.wrap = WrapWord
+	return element
+}
+
+// SetWrap sets how the lines are wrapped when they are wider than the node
+// @parma wrap: WrapWord, WrapChar or WrapNone
+func (t *Text) SetWrap(wrap uint8) error {
+	if t.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if t.wrap != wrap {
+		t.wrap = wrap
+		invalidateLayout()
+		Render()
+	}
+	return nil
+}
+
+// GetWrap returns how the lines are wrapped
+func (t *Text) GetWrap() (uint8, error) {
+	if t.unMount {
+		return t.wrap, errors.New(OperatingEmptyNodeError)
+	}
+	return t.wrap, nil
+}
+
+// textRender paints a text node, only the characters are painted when the node has no background color
+// @parma t: pointer to the text struct
+// @return the render result of the node
+func textRender(t *Text) bool {
+	if t.clip.empty() {
+		return false
+	}
+	style := t.style
+	if style.BackGroundColor.IsSet() {
+		for i := t.clip.top; i < t.clip.bottom; i++ {
+			for k := t.clip.left; k < t.clip.right; k++ {
+				screenBuffer.set(k, i, styleCell(style, ' '), t.clip)
+			}
+		}
+	}
+	if !style.ShowText {
+		return true
+	}
+
+	content := t.childArea
+	for row, line := range wrapText(t.spans.runes(), content.width(), t.wrap) {
+		y := content.top + row
+		if y >= t.childClip.bottom {
+			break
+		}
+		for column, textRune := range line {
+			textCell := spanCell(style, t.spans[textRune.span], textRune.char)
+			if !textCell.backGroundColor.IsSet() { // Transparent cells keep the background below them
+				if below := screenBuffer.get(content.left+column, y); below != nil {
+					textCell.backGroundColor = below.backGroundColor
+				}
+			}
+			screenBuffer.set(content.left+column, y, textCell, t.childClip)
+		}
+	}
+	return true
+}
+
+// wrapText splits text into lines, a new line always starts after \n
+// @parma runes: the characters of the text width: the number of cells of a line wrap: WrapWord, WrapChar or WrapNone
+// @return the lines without the line breaks
+func wrapText(runes []styledRune, width int, wrap uint8) [][]styledRune {
+	lines := [][]styledRune{}
+	start := 0
+	for index := 0; index <= len(runes); index++ {
+		if index < len(runes) && runes[index].char != '\n' {
+			continue
+		}
+		lines = append(lines, wrapLine(runes[start:index], width, wrap)...)
+		start = index + 1
+	}
+	return lines
+}
+
+// wrapLine splits a line that contains no \n
+// @parma line: the characters of the line width: the number of cells of a line wrap: WrapWord, WrapChar or WrapNone
+// @return the wrapped lines
+func wrapLine(line []styledRune, width int, wrap uint8) [][]styledRune {
+	if wrap == WrapNone || width <= 0 || len(line) <= width {
+		return [][]styledRune{line}
+	}
+	lines := [][]styledRune{}
+	for len(line) > width {
+		cut := width
+		next := width
+		if wrap == WrapWord { // Break after the last space that fits, words longer than the line are cut
+			for index := width; index > 0; index-- {
+				if line[index].char == ' ' {
+					cut, next = index, index+1
+					break
+				}
+			}
+		}
+		lines = append(lines, line[:cut])
+		line = line[next:]
+	}
+	return append(lines, line)
+}
+
+// measureContent measures the text of a text node
+// @parma width: the number of cells of a line, the lines are not wrapped when it is not positive
+// @return the width of the longest line and the number of lines
+func (t *Text) measureContent(width int) (int, int) {
+	wrap := t.wrap
+	if width <= 0 {
+		wrap = WrapNone
+	}
+	lines := wrapText(t.spans.runes(), width, wrap)
+	longest := 0
+	for _, line := range lines {
+		longest = maxInt(longest, len(line))
+	}
+	if t.spans.Len() == 0 {
+		return 0, 0
+	}
+	return longest, len(lines)
+}
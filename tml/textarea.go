@@ -0,0 +1,639 @@
+package tml
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// textRow a row of a text area on the screen, a line of the content is wrapped into one or more rows
+type textRow struct {
+	start int  //index of the first rune of the row
+	end   int  //index after the last rune of the row, the line break is not included
+	line  int  //index of the line of the row
+	first bool //whether the row is the first row of its line
+}
+
+// textEdit an edit kept by the undo history, it replaced removed with inserted at start
+type textEdit struct {
+	start    int    //index of the first replaced rune
+	removed  []rune //the runes before the edit
+	inserted []rune //the runes after the edit
+	cursor   int    //the cursor before the edit
+	anchor   int    //the selection anchor before the edit
+}
+
+// TextArea a multi-line text editor, its content is kept in a gap buffer and can be read and replaced with GetValue and SetValue.
+// The lines are wrapped by WrapWord, WrapChar or WrapNone and the rows scroll vertically, or in both directions when they are not wrapped.
+// Shift extends the selection, Ctrl moves by words and to the ends of the content, Ctrl+Z undoes, Ctrl+Y redoes and Ctrl+A selects all
+type TextArea struct {
+	Quadrilateral            //inherited struct
+	buffer        gapBuffer  //the content
+	lines         int        //number of lines of the content
+	cursor        int        //index of the rune the cursor is in front of
+	anchor        int        //the other end of the selection, equal to cursor when nothing is selected
+	column        int        //the column the cursor returns to when it moves up and down
+	wrap          uint8      //how the lines are wrapped, see WrapWord
+	lineNumbers   bool       //whether the line numbers are shown on the left
+	tabWidth      int        //number of columns between two tab stops
+	expandTabs    bool       //whether Tab inserts spaces instead of a tab
+	undo          []textEdit //the edits that can be undone, the last one first
+	redo          []textEdit //the undone edits that can be redone
+	version       int        //incremented by every change of the content or of the way it is wrapped
+	rows          []textRow  //the rows of the last wrap
+	rowsWidth     int        //the width the rows were wrapped at
+	rowsVersion   int        //the version the rows were wrapped at
+}
+
+// CreateTextArea Creates an empty text area of 40 by 10 cells
+// @parma name: the name of the node, does not force uniqueness
+// @return the text area, loaded into each global repository before it returns
+func CreateTextArea(name string) *TextArea {
+	element := new(TextArea)
+	mountQuadrilateral(element, &element.Quadrilateral, TextAreaTag, name)
+	element.volume = CanvasVolume{Width: 40, Height: 10}
+	element.overflow = CanvasOverflow{Horizontal: OverflowHidden, Vertical: OverflowAuto}
+	element.lines = 1
+	element.tabWidth = 4
+	element.wrap = WrapWord
+	element.rowsVersion = -1
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		textArea := node.(*TextArea)
+		if !textArea.editByKey() {
+			forwardKey(textArea)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*TextArea).moveByMouse()
+	})
+
+	return element
+}
+
+// SetValue replaces the content without triggering OnChange, the undo history is cleared and the cursor is moved to the start
+// @parma value: the new content
+func (ta *TextArea) SetValue(value string) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.buffer = gapBuffer{}
+	ta.buffer.insert(0, []rune(value))
+	ta.lines = 1 + countLines([]rune(value))
+	ta.cursor, ta.anchor, ta.column = 0, 0, 0
+	ta.undo, ta.redo = nil, nil
+	ta.contentChanged()
+	ta.scroll.x, ta.scroll.y = 0, 0
+	return nil
+}
+
+// GetValue returns the content
+func (ta *TextArea) GetValue() (string, error) {
+	if ta.unMount {
+		return ta.buffer.String(), errors.New(OperatingEmptyNodeError)
+	}
+	return ta.buffer.String(), nil
+}
+
+// SetWrap sets how the lines are wrapped, the rows also scroll sideways when they are not wrapped
+// @parma wrap: WrapWord, WrapChar or WrapNone
+func (ta *TextArea) SetWrap(wrap uint8) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.wrap = wrap
+	ta.overflow.Horizontal = OverflowHidden
+	if wrap == WrapNone {
+		ta.overflow.Horizontal = OverflowAuto
+	}
+	ta.contentChanged()
+	return nil
+}
+
+// SetLineNumbers shows or hides the line numbers
+// @parma show: whether the line numbers are shown
+func (ta *TextArea) SetLineNumbers(show bool) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.lineNumbers = show
+	ta.contentChanged()
+	return nil
+}
+
+// SetTabWidth sets the number of columns between two tab stops
+// @parma width: the number of columns, at least 1
+func (ta *TextArea) SetTabWidth(width int) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.tabWidth = maxInt(width, 1)
+	ta.contentChanged()
+	return nil
+}
+
+// SetExpandTabs sets whether Tab inserts spaces up to the next tab stop instead of a tab
+// @parma expand: whether spaces are inserted
+func (ta *TextArea) SetExpandTabs(expand bool) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.expandTabs = expand
+	return nil
+}
+
+// SetCursor moves the cursor and clears the selection
+// @parma position: index of the rune the cursor is put in front of, it is clamped to the content
+func (ta *TextArea) SetCursor(position int) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.moveCursor(position, false)
+	return nil
+}
+
+// GetCursor returns the index of the rune the cursor is in front of
+func (ta *TextArea) GetCursor() (int, error) {
+	if ta.unMount {
+		return ta.cursor, errors.New(OperatingEmptyNodeError)
+	}
+	return ta.cursor, nil
+}
+
+// SetSelection selects the runes between two indexes, the cursor is put at end
+// @parma start: the fixed end of the selection end: the end the cursor is put at
+func (ta *TextArea) SetSelection(start, end int) error {
+	if ta.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ta.anchor = maxInt(minInt(start, ta.buffer.Len()), 0)
+	ta.moveCursor(end, true)
+	return nil
+}
+
+// GetSelection returns the selected runes as a range
+// @return the start and the end of the selection, they are equal when nothing is selected
+func (ta *TextArea) GetSelection() (int, int, error) {
+	start, end := ta.selection()
+	if ta.unMount {
+		return start, end, errors.New(OperatingEmptyNodeError)
+	}
+	return start, end, nil
+}
+
+// Undo reverts the last edit
+// @return whether there was an edit to revert
+func (ta *TextArea) Undo() (bool, error) {
+	if ta.unMount {
+		return false, errors.New(OperatingEmptyNodeError)
+	}
+	if len(ta.undo) == 0 {
+		return false, nil
+	}
+	edit := ta.undo[len(ta.undo)-1]
+	ta.undo = ta.undo[:len(ta.undo)-1]
+	ta.apply(edit.start, edit.start+len(edit.inserted), edit.removed)
+	ta.cursor, ta.anchor = edit.cursor, edit.anchor
+	ta.redo = append(ta.redo, edit)
+	ta.edited()
+	return true, nil
+}
+
+// Redo applies the last reverted edit again
+// @return whether there was an edit to apply
+func (ta *TextArea) Redo() (bool, error) {
+	if ta.unMount {
+		return false, errors.New(OperatingEmptyNodeError)
+	}
+	if len(ta.redo) == 0 {
+		return false, nil
+	}
+	edit := ta.redo[len(ta.redo)-1]
+	ta.redo = ta.redo[:len(ta.redo)-1]
+	ta.apply(edit.start, edit.start+len(edit.removed), edit.inserted)
+	ta.cursor = edit.start + len(edit.inserted)
+	ta.anchor = ta.cursor
+	ta.undo = append(ta.undo, edit)
+	ta.edited()
+	return true, nil
+}
+
+// selection returns the selected range in order
+func (ta *TextArea) selection() (int, int) {
+	return minInt(ta.cursor, ta.anchor), maxInt(ta.cursor, ta.anchor)
+}
+
+// apply replaces a range of the buffer
+// @parma start: index of the first replaced rune end: index after the last replaced rune insert: the new runes
+func (ta *TextArea) apply(start, end int, insert []rune) {
+	ta.lines += countLines(insert) - countLines(ta.buffer.slice(start, end))
+	ta.buffer.delete(start, end)
+	ta.buffer.insert(start, insert)
+}
+
+// replaceSelection replaces the selected runes, records the edit in the undo history and triggers OnChange
+// @parma insert: the runes put in place of the selection
+func (ta *TextArea) replaceSelection(insert []rune) {
+	start, end := ta.selection()
+	if start == end && len(insert) == 0 {
+		return
+	}
+	edit := textEdit{start: start, removed: ta.buffer.slice(start, end), inserted: append([]rune{}, insert...), cursor: ta.cursor, anchor: ta.anchor}
+	ta.apply(start, end, insert)
+	ta.cursor = start + len(insert)
+	ta.anchor = ta.cursor
+
+	if last := len(ta.undo) - 1; last >= 0 && canMergeEdits(ta.undo[last], edit) { // Typing a word is undone at once
+		ta.undo[last].inserted = append(ta.undo[last].inserted, edit.inserted...)
+	} else {
+		ta.undo = append(ta.undo, edit)
+		if len(ta.undo) > TextAreaHistory {
+			ta.undo = ta.undo[1:]
+		}
+	}
+	ta.redo = nil
+	ta.edited()
+}
+
+// canMergeEdits reports whether an edit continues the typing of the previous one
+func canMergeEdits(previous, edit textEdit) bool {
+	if len(previous.removed) > 0 || len(edit.removed) > 0 || len(previous.inserted) == 0 || len(edit.inserted) != 1 {
+		return false
+	}
+	lastRune := previous.inserted[len(previous.inserted)-1]
+	return edit.start == previous.start+len(previous.inserted) && !unicode.IsSpace(lastRune) && !unicode.IsSpace(edit.inserted[0])
+}
+
+// edited refreshes the text area after the user changed the content
+func (ta *TextArea) edited() {
+	ta.contentChanged()
+	ta.keepColumn()
+	ta.scrollToCursor()
+	triggerEvent(ta, OnChange, ta)
+}
+
+// contentChanged discards the wrapped rows and renders the text area again
+func (ta *TextArea) contentChanged() {
+	ta.version++
+	invalidateLayout()
+	Render()
+}
+
+// moveCursor moves the cursor and keeps it visible
+// @parma position: the new index, clamped to the content extend: whether the selection is extended instead of cleared
+func (ta *TextArea) moveCursor(position int, extend bool) {
+	ta.moveCursorKeepColumn(position, extend)
+	ta.keepColumn()
+}
+
+// moveCursorKeepColumn moves the cursor without changing the column that vertical moves return to
+func (ta *TextArea) moveCursorKeepColumn(position int, extend bool) {
+	ta.cursor = maxInt(minInt(position, ta.buffer.Len()), 0)
+	if !extend {
+		ta.anchor = ta.cursor
+	}
+	ta.scrollToCursor()
+	Render()
+}
+
+// keepColumn remembers the column of the cursor for the next vertical move
+func (ta *TextArea) keepColumn() {
+	rows := ta.currentRows()
+	ta.column = ta.columnOf(rows[rowOf(rows, ta.cursor)], ta.cursor)
+}
+
+// moveRows moves the cursor up or down by rows, it stays in the remembered column
+// @parma count: number of rows, negative moves up extend: whether the selection is extended
+func (ta *TextArea) moveRows(count int, extend bool) {
+	rows := ta.currentRows()
+	row := maxInt(minInt(rowOf(rows, ta.cursor)+count, len(rows)-1), 0)
+	ta.moveCursorKeepColumn(ta.indexAt(rows[row], ta.column), extend)
+}
+
+// editByKey edits the content with the key of the last keyboard event
+// @return whether the key was used
+func (ta *TextArea) editByKey() bool {
+	extend := KeyModifier&ModShift != 0
+	control := KeyModifier&ModCtrl != 0
+	event := ta.KeyEvent
+	rows := ta.currentRows()
+	row := rows[rowOf(rows, ta.cursor)]
+
+	switch event.Key {
+	case KeyArrowLeft:
+		if control {
+			ta.moveCursor(wordStart(ta.buffer.runes(), ta.cursor), extend)
+		} else if start, end := ta.selection(); start != end && !extend {
+			ta.moveCursor(start, false)
+		} else {
+			ta.moveCursor(ta.cursor-1, extend)
+		}
+	case KeyArrowRight:
+		if control {
+			ta.moveCursor(wordEnd(ta.buffer.runes(), ta.cursor), extend)
+		} else if start, end := ta.selection(); start != end && !extend {
+			ta.moveCursor(end, false)
+		} else {
+			ta.moveCursor(ta.cursor+1, extend)
+		}
+	case KeyArrowUp:
+		ta.moveRows(-1, extend)
+	case KeyArrowDown:
+		ta.moveRows(1, extend)
+	case KeyPgup:
+		ta.moveRows(-maxInt(ta.scroll.viewport.height()-1, 1), extend)
+	case KeyPgdn:
+		ta.moveRows(maxInt(ta.scroll.viewport.height()-1, 1), extend)
+	case KeyHome:
+		if control {
+			ta.moveCursor(0, extend)
+		} else {
+			ta.moveCursor(row.start, extend)
+		}
+	case KeyEnd:
+		if control {
+			ta.moveCursor(ta.buffer.Len(), extend)
+		} else {
+			ta.moveCursor(row.end, extend)
+		}
+	case KeyCtrlA:
+		ta.anchor = 0
+		ta.moveCursor(ta.buffer.Len(), true)
+	case KeyBackspace, KeyBackspace2:
+		if start, end := ta.selection(); start == end {
+			ta.anchor = maxInt(ta.cursor-1, 0)
+		}
+		ta.replaceSelection(nil)
+	case KeyDelete:
+		if start, end := ta.selection(); start == end {
+			ta.anchor = minInt(ta.cursor+1, ta.buffer.Len())
+		}
+		ta.replaceSelection(nil)
+	case KeyEnter:
+		ta.replaceSelection([]rune{'\n'})
+	case KeyTab:
+		if extend { // Shift+Tab moves the focus
+			return false
+		}
+		if ta.expandTabs {
+			spaces := ta.tabWidth - ta.columnOf(row, ta.cursor)%ta.tabWidth
+			ta.replaceSelection([]rune(strings.Repeat(" ", spaces)))
+		} else {
+			ta.replaceSelection([]rune{'\t'})
+		}
+	case KeySpace:
+		ta.replaceSelection([]rune{' '})
+	case KeyCtrlZ:
+		ta.Undo()
+	case KeyCtrlY:
+		ta.Redo()
+	default:
+		if event.Key != 0 || event.Rune == 0 || !unicode.IsPrint(event.Rune) {
+			return false
+		}
+		ta.replaceSelection([]rune{event.Rune})
+	}
+	return true
+}
+
+// moveByMouse puts the cursor under the left button, dragging selects
+func (ta *TextArea) moveByMouse() {
+	if ta.mouse.Button != MouseLeft || (ta.mouse.Action != MousePress && ta.mouse.Action != MouseMove) {
+		return
+	}
+	ensureLayout()
+	rows := ta.currentRows()
+	viewport := ta.scroll.viewport
+	row := ta.scroll.y + ta.mouse.Y - viewport.top
+	if row >= len(rows) {
+		ta.moveCursor(ta.buffer.Len(), ta.mouse.Action == MouseMove)
+		return
+	}
+	column := ta.scroll.x + ta.mouse.X - viewport.left - ta.gutterWidth()
+	ta.moveCursor(ta.indexAt(rows[maxInt(row, 0)], column), ta.mouse.Action == MouseMove)
+}
+
+// scrollToCursor scrolls the least distance that brings the cursor into the viewport
+func (ta *TextArea) scrollToCursor() {
+	ensureLayout()
+	if !ta.displayed {
+		return
+	}
+	viewport := ta.scroll.viewport
+	textWidth := viewport.width() - ta.gutterWidth()
+	rows := ta.currentRows()
+	row := rowOf(rows, ta.cursor)
+	column := ta.columnOf(rows[row], ta.cursor)
+
+	x, y := ta.scroll.x, ta.scroll.y
+	if row >= y+viewport.height() {
+		y = row - viewport.height() + 1
+	}
+	if row < y {
+		y = row
+	}
+	if column >= x+textWidth {
+		x = column - textWidth + 1
+	}
+	if column < x {
+		x = column
+	}
+	scrollNode(ta, x, y)
+}
+
+// gutterWidth returns the number of columns taken by the line numbers
+func (ta *TextArea) gutterWidth() int {
+	if !ta.lineNumbers {
+		return 0
+	}
+	return len(strconv.Itoa(ta.lines)) + 1
+}
+
+// currentRows returns the rows wrapped at the width of the viewport computed by the last layout pass
+func (ta *TextArea) currentRows() []textRow {
+	ensureLayout()
+	return ta.wrapRows(ta.scroll.viewport.width() - ta.gutterWidth())
+}
+
+// wrapRows wraps the lines of the content into rows, the rows are kept until the content or the width changes
+// @parma width: the number of columns of a row
+func (ta *TextArea) wrapRows(width int) []textRow {
+	if ta.rowsVersion == ta.version && ta.rowsWidth == width {
+		return ta.rows
+	}
+	runes := ta.buffer.runes()
+	rows := make([]textRow, 0, ta.lines)
+	line, start := 0, 0
+	for index := 0; index <= len(runes); index++ {
+		if index < len(runes) && runes[index] != '\n' {
+			continue
+		}
+		rows = ta.wrapLine(rows, runes, start, index, line, width)
+		line++
+		start = index + 1
+	}
+	ta.rows, ta.rowsWidth, ta.rowsVersion = rows, width, ta.version
+	return rows
+}
+
+// wrapLine wraps a line into rows
+// @parma rows: the rows of the previous lines runes: the content start: index of the first rune of the line end: index of the line break
+// line: index of the line width: the number of columns of a row
+// @return rows with the rows of the line appended
+func (ta *TextArea) wrapLine(rows []textRow, runes []rune, start, end, line, width int) []textRow {
+	row := textRow{start: start, line: line, first: true}
+	column := 0
+	lastSpace := -1
+	for index := start; index < end; index++ {
+		runeWidth := ta.runeWidth(runes[index], column)
+		if ta.wrap != WrapNone && width > 0 && column+runeWidth > width && index > row.start {
+			cut := index
+			if ta.wrap == WrapWord && lastSpace >= row.start { // The row ends after the last space that fits
+				cut = lastSpace + 1
+			}
+			row.end = cut
+			rows = append(rows, row)
+			row = textRow{start: cut, line: line}
+			column = ta.displayWidth(runes[cut:index])
+			lastSpace = -1
+			runeWidth = ta.runeWidth(runes[index], column)
+		}
+		if runes[index] == ' ' || runes[index] == '\t' {
+			lastSpace = index
+		}
+		column += runeWidth
+	}
+	row.end = end
+	return append(rows, row)
+}
+
+// runeWidth returns the number of columns of a rune, a tab reaches the next tab stop
+// @parma char: the rune column: the column the rune starts at
+func (ta *TextArea) runeWidth(char rune, column int) int {
+	if char == '\t' {
+		return ta.tabWidth - column%ta.tabWidth
+	}
+	return 1
+}
+
+// displayWidth returns the number of columns of runes that start a row
+func (ta *TextArea) displayWidth(runes []rune) int {
+	column := 0
+	for _, char := range runes {
+		column += ta.runeWidth(char, column)
+	}
+	return column
+}
+
+// columnOf returns the column of a position in its row
+func (ta *TextArea) columnOf(row textRow, position int) int {
+	return ta.displayWidth(ta.buffer.slice(row.start, position))
+}
+
+// indexAt returns the position of a row that is closest to a column
+func (ta *TextArea) indexAt(row textRow, column int) int {
+	current := 0
+	for index, char := range ta.buffer.slice(row.start, row.end) {
+		runeWidth := ta.runeWidth(char, current)
+		if current+runeWidth > column {
+			return row.start + index
+		}
+		current += runeWidth
+	}
+	return row.end
+}
+
+// rowOf finds the row that holds a position, a position at the end of a wrapped row belongs to the next row
+func rowOf(rows []textRow, position int) int {
+	low, high := 0, len(rows)-1
+	for low < high {
+		middle := (low + high + 1) / 2
+		if rows[middle].start <= position {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+	return low
+}
+
+// countLines returns the number of line breaks of runes
+func countLines(runes []rune) int {
+	count := 0
+	for _, char := range runes {
+		if char == '\n' {
+			count++
+		}
+	}
+	return count
+}
+
+// measureContent measures the rows of a text area
+// @parma width: the width of the viewport, the lines are not wrapped when it is not positive
+// @return the width of the longest row with the line numbers and the cursor, and the number of rows
+func (ta *TextArea) measureContent(width int) (int, int) {
+	gutter := ta.gutterWidth()
+	rows := ta.wrapRows(maxInt(width-gutter, 0))
+	longest := 0
+	for _, row := range rows {
+		longest = maxInt(longest, ta.displayWidth(ta.buffer.slice(row.start, row.end)))
+	}
+	return gutter + longest + 1, len(rows)
+}
+
+// textAreaRender paints the visible rows of a text area, the line numbers stay on the left when the rows scroll sideways
+// @parma ta: pointer to the text area struct
+// @return the render result of the node
+func textAreaRender(ta *TextArea) bool {
+	if ta.clip.empty() {
+		return false
+	}
+	style := ta.style
+	boxDrawing(&ta.Canvas, style)
+	drawScrollBars(&ta.Canvas, ta.clip)
+
+	viewport := ta.scroll.viewport
+	gutter := ta.gutterWidth()
+	textClip := ta.childClip
+	textClip.left = maxInt(textClip.left, viewport.left+gutter)
+	rows := ta.wrapRows(viewport.width() - gutter)
+	start, end := ta.selection()
+	numberCell := styleCell(style, ' ')
+	numberCell.attribute |= AttrDim
+
+	for row := ta.scroll.y; row < len(rows) && row < ta.scroll.y+viewport.height(); row++ {
+		y := viewport.top + row - ta.scroll.y
+		if gutter > 0 && rows[row].first {
+			number := strconv.Itoa(rows[row].line + 1)
+			for index, char := range number {
+				numberCell.char = char
+				screenBuffer.set(viewport.left+gutter-1-len(number)+index, y, numberCell, ta.childClip)
+			}
+		}
+
+		column := 0
+		for index, char := range ta.buffer.slice(rows[row].start, rows[row].end) {
+			position := rows[row].start + index
+			runeWidth := ta.runeWidth(char, column)
+			textCell := styleCell(style, char)
+			if char == '\t' {
+				textCell.char = ' '
+			}
+			if position >= start && position < end {
+				textCell.attribute |= AttrReverse
+			}
+			for cellIndex := 0; cellIndex < runeWidth; cellIndex++ {
+				screenBuffer.set(viewport.left+gutter+column+cellIndex-ta.scroll.x, y, textCell, textClip)
+			}
+			column += runeWidth
+		}
+	}
+
+	if SelectNode == Node(ta) {
+		row := rowOf(rows, ta.cursor)
+		x := viewport.left + gutter + ta.columnOf(rows[row], ta.cursor) - ta.scroll.x
+		screenBuffer.placeCursor(x, viewport.top+row-ta.scroll.y, textClip)
+	}
+	return true
+}
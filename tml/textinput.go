@@ -0,0 +1,395 @@
+package tml
+
+import (
+	"errors"
+	"unicode"
+)
+
+// Input a single-line text input, its value is the text of the node. It shows the terminal cursor while it is selected and edits
+// its value with the keys, OnChange is triggered by every edit of the user and OnSubmit by Enter.
+// Shift extends the selection, Ctrl moves by words, Ctrl+A selects all, Ctrl+U and Ctrl+K delete to the start and to the end
+type Input struct {
+	Quadrilateral        //inherited struct
+	cursor        int    //index of the rune the cursor is in front of
+	anchor        int    //the other end of the selection, equal to cursor when nothing is selected
+	offset        int    //index of the first visible rune when the cursor was last moved, see visibleOffset
+	placeholder   string //text shown in dim when the value is empty
+	maxLength     int    //maximum number of runes of the value, 0 means no maximum
+	mask          rune   //the rune shown instead of every rune of the value, 0 shows the value
+}
+
+// CreateInput Creates a single-line text input that is 20 cells wide
+// @parma name: the name of the node, does not force uniqueness
+// @return the input, loaded into each global repository before it returns
+func CreateInput(name string) *Input {
+	element := new(Input)
+	mountQuadrilateral(element, &element.Quadrilateral, InputTag, name)
+	element.volume = CanvasVolume{Width: 20, HeightUnit: SizeFit}
+	element.style.BackGroundColor = BrightBlackColor
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		input := node.(*Input)
+		if !input.editByKey() {
+			forwardKey(input)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*Input).moveByMouse()
+	})
+
+	return element
+}
+
+// SetValue sets the value without triggering OnChange, it is cut to the maximum length and the cursor is moved to its end
+// @parma value: the new value
+func (in *Input) SetValue(value string) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	runes := in.clampLength([]rune(value))
+	in.cursor, in.anchor = len(runes), len(runes)
+	return in.SetText(string(runes))
+}
+
+// SetText sets the value without moving the cursor to its end, the cursor and the selection are kept inside the value
+// @parma text: the new value
+func (in *Input) SetText(text string) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	in.keepInValue(len([]rune(text)))
+	err := in.Quadrilateral.SetText(text)
+	in.scrollToCursor()
+	return err
+}
+
+// SetStyledText sets the value from the text of the spans, the cursor and the selection are kept inside the value
+// @parma text: the new value
+func (in *Input) SetStyledText(text StyledText) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	in.keepInValue(text.Len())
+	err := in.Quadrilateral.SetStyledText(text)
+	in.scrollToCursor()
+	return err
+}
+
+// GetValue returns the value
+func (in *Input) GetValue() (string, error) {
+	if in.unMount {
+		return in.text, errors.New(OperatingEmptyNodeError)
+	}
+	return in.text, nil
+}
+
+// SetPlaceholder sets the text shown in dim when the value is empty
+// @parma placeholder: the text
+func (in *Input) SetPlaceholder(placeholder string) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	in.placeholder = placeholder
+	Render()
+	return nil
+}
+
+// SetMaxLength sets the maximum number of runes of the value, a longer value is cut
+// @parma length: the maximum, 0 means no maximum
+func (in *Input) SetMaxLength(length int) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	in.maxLength = maxInt(length, 0)
+	if runes := []rune(in.text); in.maxLength > 0 && len(runes) > in.maxLength {
+		return in.SetValue(in.text)
+	}
+	return nil
+}
+
+// SetMask hides the value behind a rune, used by password inputs
+// @parma mask: the rune shown instead of every rune of the value, 0 shows the value
+func (in *Input) SetMask(mask rune) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	in.mask = mask
+	Render()
+	return nil
+}
+
+// SetCursor moves the cursor and clears the selection
+// @parma position: index of the rune the cursor is put in front of, it is clamped to the value
+func (in *Input) SetCursor(position int) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	in.moveCursor(position, false)
+	return nil
+}
+
+// GetCursor returns the index of the rune the cursor is in front of
+func (in *Input) GetCursor() (int, error) {
+	if in.unMount {
+		return in.cursor, errors.New(OperatingEmptyNodeError)
+	}
+	return in.cursor, nil
+}
+
+// SetSelection selects the runes between two indexes, the cursor is put at end
+// @parma start: the fixed end of the selection end: the end the cursor is put at
+func (in *Input) SetSelection(start, end int) error {
+	if in.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	length := len([]rune(in.text))
+	in.anchor = maxInt(minInt(start, length), 0)
+	in.moveCursor(end, true)
+	return nil
+}
+
+// GetSelection returns the selected runes as a range
+// @return the start and the end of the selection, they are equal when nothing is selected
+func (in *Input) GetSelection() (int, int, error) {
+	start, end := in.selection()
+	if in.unMount {
+		return start, end, errors.New(OperatingEmptyNodeError)
+	}
+	return start, end, nil
+}
+
+// selection returns the selected range in order
+func (in *Input) selection() (int, int) {
+	return minInt(in.cursor, in.anchor), maxInt(in.cursor, in.anchor)
+}
+
+// clampLength cuts runes to the maximum length
+func (in *Input) clampLength(runes []rune) []rune {
+	if in.maxLength > 0 && len(runes) > in.maxLength {
+		return runes[:in.maxLength]
+	}
+	return runes
+}
+
+// keepInValue clamps the cursor and the selection to a value of a given length
+// @parma length: number of runes of the value
+func (in *Input) keepInValue(length int) {
+	in.cursor = maxInt(minInt(in.cursor, length), 0)
+	in.anchor = maxInt(minInt(in.anchor, length), 0)
+	in.offset = maxInt(minInt(in.offset, length), 0)
+}
+
+// moveCursor moves the cursor and keeps it visible
+// @parma position: the new index, clamped to the value extend: whether the selection is extended instead of cleared
+func (in *Input) moveCursor(position int, extend bool) {
+	in.cursor = maxInt(minInt(position, len([]rune(in.text))), 0)
+	if !extend {
+		in.anchor = in.cursor
+	}
+	in.scrollToCursor()
+	Render()
+}
+
+// scrollToCursor stores the offset that keeps the cursor visible, it is the start of the next move
+func (in *Input) scrollToCursor() {
+	ensureLayout()
+	if in.displayed {
+		in.offset = in.visibleOffset(in.childArea.width())
+	}
+}
+
+// visibleOffset computes the first visible rune from the last offset with the least movement that shows the cursor
+// @parma width: width of the content area
+// @return index of the first visible rune
+func (in *Input) visibleOffset(width int) int {
+	offset := in.offset
+	if in.cursor < offset {
+		offset = in.cursor
+	}
+	if in.cursor >= offset+width {
+		offset = in.cursor - width + 1
+	}
+	return maxInt(minInt(offset, len([]rune(in.text))+1-width), 0)
+}
+
+// replaceSelection replaces the selected runes and triggers OnChange when the value changes
+// @parma insert: the runes put in place of the selection, they are cut when the value would exceed the maximum length
+func (in *Input) replaceSelection(insert []rune) {
+	runes := []rune(in.text)
+	start, end := in.selection()
+	if in.maxLength > 0 {
+		insert = insert[:minInt(len(insert), maxInt(in.maxLength-len(runes)+end-start, 0))]
+	}
+	value := append(append(append([]rune{}, runes[:start]...), insert...), runes[end:]...)
+	in.cursor = start + len(insert)
+	in.anchor = in.cursor
+	if string(value) == in.text {
+		in.scrollToCursor()
+		Render()
+		return
+	}
+	in.SetText(string(value))
+	triggerEvent(in, OnChange, in)
+}
+
+// editByKey edits the value with the key of the last keyboard event
+// @return whether the key was used
+func (in *Input) editByKey() bool {
+	runes := []rune(in.text)
+	extend := KeyModifier&ModShift != 0
+	word := KeyModifier&ModCtrl != 0
+	event := in.KeyEvent
+
+	if event.Key == KeyEsc && event.Rune != 0 { // Alt+b and Alt+f move by words like readline
+		switch event.Rune {
+		case 'b':
+			in.moveCursor(wordStart(runes, in.cursor), false)
+		case 'f':
+			in.moveCursor(wordEnd(runes, in.cursor), false)
+		default:
+			return false
+		}
+		return true
+	}
+
+	switch event.Key {
+	case KeyArrowLeft:
+		if word {
+			in.moveCursor(wordStart(runes, in.cursor), extend)
+		} else if start, end := in.selection(); start != end && !extend {
+			in.moveCursor(start, false)
+		} else {
+			in.moveCursor(in.cursor-1, extend)
+		}
+	case KeyArrowRight:
+		if word {
+			in.moveCursor(wordEnd(runes, in.cursor), extend)
+		} else if start, end := in.selection(); start != end && !extend {
+			in.moveCursor(end, false)
+		} else {
+			in.moveCursor(in.cursor+1, extend)
+		}
+	case KeyHome, KeyCtrlA:
+		if event.Key == KeyCtrlA { // Select all
+			in.anchor = 0
+			in.moveCursor(len(runes), true)
+		} else {
+			in.moveCursor(0, extend)
+		}
+	case KeyEnd, KeyCtrlE:
+		in.moveCursor(len(runes), extend)
+	case KeyBackspace, KeyBackspace2:
+		if start, end := in.selection(); start == end {
+			in.anchor = maxInt(in.cursor-1, 0)
+		}
+		in.replaceSelection(nil)
+	case KeyDelete:
+		if start, end := in.selection(); start == end {
+			in.anchor = minInt(in.cursor+1, len(runes))
+		}
+		in.replaceSelection(nil)
+	case KeyCtrlW: // Delete the word before the cursor
+		in.anchor = wordStart(runes, in.cursor)
+		in.replaceSelection(nil)
+	case KeyCtrlU:
+		in.anchor = 0
+		in.replaceSelection(nil)
+	case KeyCtrlK:
+		in.anchor = len(runes)
+		in.replaceSelection(nil)
+	case KeyEnter:
+		triggerEvent(in, OnSubmit, in)
+	case KeySpace:
+		in.replaceSelection([]rune{' '})
+	default:
+		if event.Key != 0 || event.Rune == 0 || !unicode.IsPrint(event.Rune) {
+			return false
+		}
+		in.replaceSelection([]rune{event.Rune})
+	}
+	return true
+}
+
+// moveByMouse puts the cursor under the left button, dragging selects
+func (in *Input) moveByMouse() {
+	if in.mouse.Button != MouseLeft || (in.mouse.Action != MousePress && in.mouse.Action != MouseMove) {
+		return
+	}
+	ensureLayout()
+	in.moveCursor(in.visibleOffset(in.childArea.width())+in.mouse.X-in.childArea.left, in.mouse.Action == MouseMove)
+}
+
+// wordStart finds the start of the word before a position, the spaces in front of the position are skipped first
+func wordStart(runes []rune, position int) int {
+	for position > 0 && unicode.IsSpace(runes[position-1]) {
+		position--
+	}
+	for position > 0 && !unicode.IsSpace(runes[position-1]) {
+		position--
+	}
+	return position
+}
+
+// wordEnd finds the end of the word after a position, the spaces after the position are skipped first
+func wordEnd(runes []rune, position int) int {
+	for position < len(runes) && unicode.IsSpace(runes[position]) {
+		position++
+	}
+	for position < len(runes) && !unicode.IsSpace(runes[position]) {
+		position++
+	}
+	return position
+}
+
+// measureContent measures the value of an input with the cell of the cursor after it
+// @parma width: unused, the value is a single line
+// @return width and height of the content
+func (in *Input) measureContent(width int) (int, int) {
+	return len([]rune(in.text)) + 1, 1
+}
+
+// inputRender paints an input, its value is scrolled sideways to keep the cursor visible, the render does not change the input
+// @parma in: pointer to the input struct
+// @return the render result of the node
+func inputRender(in *Input) bool {
+	if in.clip.empty() {
+		return false
+	}
+	style := in.style
+	boxDrawing(&in.Canvas, style)
+	content := in.childArea
+	width := content.width()
+	if width <= 0 {
+		return true
+	}
+
+	runes := []rune(in.text)
+	offset := in.visibleOffset(width) // The width may have changed since the cursor was last moved
+
+	y := content.top + (content.height()-1)/2
+	if len(runes) == 0 && in.placeholder != "" {
+		placeholderCell := styleCell(style, ' ')
+		placeholderCell.attribute |= AttrDim
+		for index, char := range []rune(in.placeholder) {
+			placeholderCell.char = char
+			screenBuffer.set(content.left+index, y, placeholderCell, in.childClip)
+		}
+	}
+
+	start, end := in.selection()
+	for index := offset; index < len(runes) && index < offset+width; index++ {
+		textCell := styleCell(style, runes[index])
+		if in.mask != 0 {
+			textCell.char = in.mask
+		}
+		if index >= start && index < end {
+			textCell.attribute |= AttrReverse
+		}
+		screenBuffer.set(content.left+index-offset, y, textCell, in.childClip)
+	}
+
+	if SelectNode == Node(in) {
+		screenBuffer.placeCursor(content.left+in.cursor-offset, y, in.childClip)
+	}
+	return true
+}
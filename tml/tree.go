@@ -0,0 +1,398 @@
+package tml
+
+import "errors"
+
+// Guide line characters of the tree
+const (
+	treeBranch     = "├─"
+	treeLastBranch = "└─"
+	treeGuide      = "│  "
+	treeSpace      = "   "
+	treeCollapsed  = '▸'
+	treeExpanded   = '▾'
+	treeLeaf       = '─'
+)
+
+// TreeItem an item of a Tree, its label is the text of the node and its children are the TreeItem children of the node.
+// Items are not drawn by themselves, the tree that holds them draws one row for every item whose ancestors are expanded
+type TreeItem struct {
+	Quadrilateral                      //inherited struct
+	expanded      bool                 //whether the children are shown
+	loader        func(item *TreeItem) //inserts the children the first time the item is expanded, nil once they are loaded
+}
+
+// treeRow a visible item of a tree and the guide lines in front of it
+type treeRow struct {
+	item   *TreeItem //the item
+	depth  int       //number of item ancestors
+	guides []bool    //for every ancestor level, whether a guide line continues below the row
+	last   bool      //whether the item is the last child of its parent
+}
+
+// Tree a scrolling view of TreeItem nodes inserted into it and into each other, one row is drawn for every visible item with guide lines.
+// Up and Down move the selected item, Right expands it or moves to its first child, Left collapses it or moves to its parent and Space toggles it.
+// OnSelectItem is triggered when the selected item changes and OnActivate by Enter. OnExpand and OnCollapse are triggered on the item and bubble to the tree
+type Tree struct {
+	Quadrilateral           //inherited struct
+	cursor        *TreeItem //the selected item
+}
+
+// CreateTreeItem Creates a collapsed tree item
+// @parma name: the name of the node, does not force uniqueness label: the text of the row
+// @return the item, loaded into each global repository before it returns
+func CreateTreeItem(name string, label string) *TreeItem {
+	element := new(TreeItem)
+	mountQuadrilateral(element, &element.Quadrilateral, TreeItemTag, name)
+	element.text = label
+	element.spans = PlainText(label)
+	return element
+}
+
+// SetExpanded expands or collapses the item, the loader is called the first time the item is expanded
+// @parma expanded: whether the children are shown
+func (ti *TreeItem) SetExpanded(expanded bool) error {
+	if ti.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	if ti.expanded == expanded {
+		return nil
+	}
+	ti.expanded = expanded
+	if expanded && ti.loader != nil { // Lazy children are inserted once
+		loader := ti.loader
+		ti.loader = nil
+		loader(ti)
+	}
+	invalidateLayout()
+	Render()
+	if expanded {
+		bubbleEvent(ti, OnExpand)
+	} else {
+		bubbleEvent(ti, OnCollapse)
+	}
+	return nil
+}
+
+// IsExpanded reports whether the children of the item are shown
+func (ti *TreeItem) IsExpanded() (bool, error) {
+	if ti.unMount {
+		return ti.expanded, errors.New(OperatingEmptyNodeError)
+	}
+	return ti.expanded, nil
+}
+
+// SetLoader sets the function that inserts the children of the item the first time it is expanded, the item can be expanded until then
+// @parma loader: the function, it receives the item
+func (ti *TreeItem) SetLoader(loader func(item *TreeItem)) error {
+	if ti.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	ti.loader = loader
+	Render()
+	return nil
+}
+
+// GetItems returns the TreeItem children of the item
+func (ti *TreeItem) GetItems() ([]*TreeItem, error) {
+	if ti.unMount {
+		return nil, errors.New(OperatingEmptyNodeError)
+	}
+	return treeItems(ti.children), nil
+}
+
+// expandable reports whether the item has or may load children
+func (ti *TreeItem) expandable() bool {
+	return ti.loader != nil || len(treeItems(ti.children)) > 0
+}
+
+// treeItems filters the TreeItem nodes of children
+func treeItems(children []Node) []*TreeItem {
+	items := []*TreeItem{}
+	for _, child := range children {
+		if item, ok := child.(*TreeItem); ok && !item.unMount && item.style.Display {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// CreateTree Creates an empty tree of 30 by 10 cells
+// @parma name: the name of the node, does not force uniqueness
+// @return the tree, loaded into each global repository before it returns
+func CreateTree(name string) *Tree {
+	element := new(Tree)
+	mountQuadrilateral(element, &element.Quadrilateral, TreeTag, name)
+	element.volume = CanvasVolume{Width: 30, Height: 10}
+	element.overflow = CanvasOverflow{Horizontal: OverflowAuto, Vertical: OverflowAuto}
+
+	element.AddEventListener(OnKeyBord, func(node Node, origen Node) {
+		tree := node.(*Tree)
+		if !tree.navigateByKey() {
+			forwardKey(tree)
+		}
+	})
+	element.AddEventListener(OnMouse, func(node Node, origen Node) {
+		node.(*Tree).selectByMouse()
+	})
+
+	return element
+}
+
+// SelectItem selects an item and scrolls it into view, its ancestors are expanded
+// @parma item: an item of the tree
+func (t *Tree) SelectItem(item *TreeItem) error {
+	if t.unMount || item.unMount {
+		return errors.New(OperatingEmptyNodeError)
+	}
+	for parent := item.parent; parent != nil && parent != Node(t); parent, _ = parent.GetParent() {
+		if parentItem, ok := parent.(*TreeItem); ok {
+			parentItem.SetExpanded(true)
+		}
+	}
+	t.moveCursor(item)
+	return nil
+}
+
+// GetSelectedItem returns the selected item, nil when the tree is empty
+func (t *Tree) GetSelectedItem() (*TreeItem, error) {
+	if t.unMount {
+		return nil, errors.New(OperatingEmptyNodeError)
+	}
+	rows := t.visibleRows()
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[t.cursorRow(rows)].item, nil
+}
+
+// visibleRows lists the items whose ancestors are expanded in the order they are drawn
+func (t *Tree) visibleRows() []treeRow {
+	rows := []treeRow{}
+	var visit func(items []*TreeItem, depth int, guides []bool)
+	visit = func(items []*TreeItem, depth int, guides []bool) {
+		for index, item := range items {
+			last := index == len(items)-1
+			rows = append(rows, treeRow{item: item, depth: depth, guides: guides, last: last})
+			if item.expanded {
+				visit(treeItems(item.children), depth+1, append(append([]bool{}, guides...), !last))
+			}
+		}
+	}
+	visit(treeItems(t.children), 0, nil)
+	return rows
+}
+
+// cursorRow finds the row of the selected item, an item hidden by a collapsed ancestor is replaced by that ancestor
+// @parma rows: the visible rows
+func (t *Tree) cursorRow(rows []treeRow) int {
+	if t.cursor == nil {
+		return 0
+	}
+	for item := Node(t.cursor); item != nil && item != Node(t); item, _ = item.GetParent() {
+		for index, row := range rows {
+			if Node(row.item) == item {
+				return index
+			}
+		}
+	}
+	return 0
+}
+
+// moveCursor selects an item and triggers OnSelectItem when the selected item changes
+// @parma item: the item
+func (t *Tree) moveCursor(item *TreeItem) {
+	if item == nil {
+		return
+	}
+	rows := t.visibleRows()
+	changed := len(rows) == 0 || rows[t.cursorRow(rows)].item != item
+	t.cursor = item
+	t.scrollToCursor()
+	Render()
+	if changed {
+		triggerEvent(t, OnSelectItem, item)
+	}
+}
+
+// moveRows moves the selection by rows
+// @parma count: number of rows, negative moves up
+func (t *Tree) moveRows(count int) {
+	rows := t.visibleRows()
+	if len(rows) == 0 {
+		return
+	}
+	row := maxInt(minInt(t.cursorRow(rows)+count, len(rows)-1), 0)
+	t.moveCursor(rows[row].item)
+}
+
+// navigateByKey moves the selection and expands or collapses the selected item with the key of the last keyboard event
+// @return whether the key was used
+func (t *Tree) navigateByKey() bool {
+	rows := t.visibleRows()
+	if len(rows) == 0 {
+		return false
+	}
+	item := rows[t.cursorRow(rows)].item
+	page := maxInt(t.scroll.viewport.height()-1, 1)
+
+	switch t.KeyEvent.Key {
+	case KeyArrowUp:
+		t.moveRows(-1)
+	case KeyArrowDown:
+		t.moveRows(1)
+	case KeyPgup:
+		t.moveRows(-page)
+	case KeyPgdn:
+		t.moveRows(page)
+	case KeyHome:
+		t.moveCursor(rows[0].item)
+	case KeyEnd:
+		t.moveCursor(rows[len(rows)-1].item)
+	case KeyArrowRight:
+		if !item.expanded && item.expandable() {
+			item.SetExpanded(true)
+		} else if children := treeItems(item.children); item.expanded && len(children) > 0 {
+			t.moveCursor(children[0])
+		}
+	case KeyArrowLeft:
+		if item.expanded {
+			item.SetExpanded(false)
+		} else if parent, ok := item.parent.(*TreeItem); ok {
+			t.moveCursor(parent)
+		}
+	case KeySpace:
+		if item.expandable() {
+			item.SetExpanded(!item.expanded)
+		}
+	case KeyEnter:
+		triggerEvent(t, OnActivate, item)
+	default:
+		return false
+	}
+	return true
+}
+
+// selectByMouse selects the item under the left button, a click on the expand marker toggles the item
+func (t *Tree) selectByMouse() {
+	if t.mouse.Button != MouseLeft || t.mouse.Action != MousePress {
+		return
+	}
+	ensureLayout()
+	rows := t.visibleRows()
+	viewport := t.scroll.viewport
+	index := t.scroll.y + t.mouse.Y - viewport.top
+	if index < 0 || index >= len(rows) {
+		return
+	}
+	row := rows[index]
+	if t.scroll.x+t.mouse.X-viewport.left == treeMarkerColumn(row.depth) && row.item.expandable() {
+		row.item.SetExpanded(!row.item.expanded)
+	}
+	t.moveCursor(row.item)
+}
+
+// scrollToCursor scrolls the least distance that brings the selected item into the viewport
+func (t *Tree) scrollToCursor() {
+	ensureLayout()
+	if !t.displayed {
+		return
+	}
+	row := t.cursorRow(t.visibleRows())
+	height := t.scroll.viewport.height()
+	y := t.scroll.y
+	if row >= y+height {
+		y = row - height + 1
+	}
+	if row < y {
+		y = row
+	}
+	scrollNode(t, t.scroll.x, y)
+}
+
+// treeMarkerColumn returns the column of the expand marker of a row
+// @parma depth: number of item ancestors of the row
+func treeMarkerColumn(depth int) int {
+	if depth == 0 {
+		return 0
+	}
+	return (depth-1)*len([]rune(treeGuide)) + len([]rune(treeBranch))
+}
+
+// treePrefix builds the guide lines and the expand marker of a row
+// @parma row: the row
+func treePrefix(row treeRow) []rune {
+	prefix := []rune{}
+	if row.depth > 0 {
+		for _, guide := range row.guides[1:] {
+			if guide {
+				prefix = append(prefix, []rune(treeGuide)...)
+			} else {
+				prefix = append(prefix, []rune(treeSpace)...)
+			}
+		}
+		if row.last {
+			prefix = append(prefix, []rune(treeLastBranch)...)
+		} else {
+			prefix = append(prefix, []rune(treeBranch)...)
+		}
+	}
+	switch {
+	case !row.item.expandable():
+		prefix = append(prefix, treeLeaf)
+	case row.item.expanded:
+		prefix = append(prefix, treeExpanded)
+	default:
+		prefix = append(prefix, treeCollapsed)
+	}
+	return append(prefix, ' ')
+}
+
+// measureContent measures the visible rows of a tree
+// @parma width: unused, the rows are not wrapped
+// @return the width of the longest row and the number of rows
+func (t *Tree) measureContent(width int) (int, int) {
+	rows := t.visibleRows()
+	longest := 0
+	for _, row := range rows {
+		longest = maxInt(longest, treeMarkerColumn(row.depth)+2+row.item.spans.Len())
+	}
+	return longest, len(rows)
+}
+
+// treeRender paints the visible rows of a tree with their guide lines, the selected item is reversed
+// @parma t: pointer to the tree struct
+// @return the render result of the node
+func treeRender(t *Tree) bool {
+	if t.clip.empty() {
+		return false
+	}
+	style := t.style
+	boxDrawing(&t.Canvas, style)
+	drawScrollBars(&t.Canvas, t.clip)
+
+	viewport := t.scroll.viewport
+	rows := t.visibleRows()
+	cursor := t.cursorRow(rows)
+	guideCell := styleCell(style, ' ')
+	guideCell.attribute |= AttrDim
+	for index := t.scroll.y; index < len(rows) && index < t.scroll.y+viewport.height(); index++ {
+		row := rows[index]
+		y := viewport.top + index - t.scroll.y
+		x := viewport.left - t.scroll.x
+		prefix := treePrefix(row)
+		for column, char := range prefix {
+			guideCell.char = char
+			screenBuffer.set(x+column, y, guideCell, t.childClip)
+		}
+
+		labelStyle := style
+		if index == cursor {
+			labelStyle.Attribute |= AttrReverse
+		}
+		x += len(prefix)
+		for column, labelRune := range row.item.spans.runes() {
+			screenBuffer.set(x+column, y, spanCell(labelStyle, row.item.spans[labelRune.span], labelRune.char), t.childClip)
+		}
+	}
+	return true
+}
@@ -1,199 +1,134 @@
 package tml
 
-// squareDrawing renders a Quadrilateral in a terminal by parsing the quadrilateral
+// squareDrawing renders a Quadrilateral in a terminal by parsing the quadrilateral, its geometry comes from the layout pass
 // @parma ql: target rendered quadrilateral
 // @return Whether the rendering is successful
 func squareDrawing(ql *Quadrilateral) bool {
 
 	style := ql.style
-	cWidth, cHeight := getCanvasSize(ql)
+	content := ql.childArea // Text flows through the content, which is scrolled for scrolling nodes
 
-	oldVolume := ql.volume
+	boxDrawing(&ql.Canvas, style)
 
-	if style.AutoSize {
-		if ql.volume.Width == Auto {
-			ql.volume.Width = cWidth
-		}
-
-		if ql.volume.Height == Auto {
-			ql.volume.Height = cHeight
-		}
-	}
-
-	parent := ql.parent
-	position := ql.position
-	volume := ql.volume
-
-	cTop := 0
-	cLeft := 0
-	cRight := 0
-	cBottom := 0
-	yStart := position.totalY
-	xStart := position.totalX
-	yEnd := yStart + volume.Height
-	xEnd := xStart + volume.Width
-
-	basicsX := ""
-	basicsY := ""
-
-	globalBuf.WriteString(hiddenCursor)
-
-	if parent != nil {
-		pPosition, _ := parent.GetPosition()
-
-		cTop = pPosition.totalY
-		cLeft = pPosition.totalX
-		cRight = cLeft + cWidth
-		cBottom = cTop + cHeight
-
-		if position.X > cWidth || position.Y > cHeight || position.X+volume.Width <= 0 || position.Y+volume.Height <= 0 { // Does not render in the parent node
-			return false
-		}
-	} else {
-		cRight = SysWidth
-		cBottom = SysHeight
-	}
-
-	if position.Type.Center != None { //Parse position.type.center
-		nType := position.Type.Center
-		if nType == PositionX || nType == PositionXY {
-			xStart = confirmSquareCenter(cRight, volume.Width)
-			xEnd = xStart + volume.Width
-		}
-		if nType == PositionY || nType == PositionXY {
-			yStart = confirmSquareCenter(cBottom, volume.Height)
-			yEnd = yStart + volume.Height
-		}
+	if style.ShowText {
+		textDrawing(ql, content)
 	}
 
-	if position.Type.Right != None { //Resolve position.type.right center and right exist at the same time, and the weight of right changes
-		nType := position.Type.Right
-		if nType == PositionX || nType == PositionXY {
-			xEnd = confirmSquareEnd(position.X, cRight)
-			xStart = xEnd - volume.Width
-		}
-		if nType == PositionY || nType == PositionXY {
-			yEnd = confirmSquareEnd(position.Y, cBottom)
-			yStart = yEnd - volume.Height
-		}
+	if ql.scroll.enabled {
+		drawScrollBars(&ql.Canvas, ql.clip)
 	}
 
-	qlYStart := yStart
-	qlXStart := xStart
-	qlYEnd := yEnd
-	qlXEnd := xEnd
-
-	yStart = confirmStartSquare(yStart, cTop) // Determine the final render position
-	xStart = confirmStartSquare(xStart, cLeft)
-	yEnd = confirmEndSquare(yEnd, cBottom)
-	xEnd = confirmEndSquare(xEnd, cRight)
+	return true
+}
 
-	if style.Color != "" {
-		globalBuf.WriteString(style.Color)
-	}
+// boxDrawing paints the background and the border of a node, widgets pass the style of their current state
+// @parma canvas: the node style: the style the box is painted with
+func boxDrawing(canvas *Canvas, style CanvasStyle) {
+	bounds := canvas.rect
+	visible := canvas.clip
 
-	if style.BackGroundColor != "" {
-		globalBuf.WriteString(style.BackGroundColor)
-	}
+	var basicsX rune = ' '
+	var basicsY rune = ' '
 
-	if style.BorderColor != "" {
-		basicsY = style.BorderColor
-		basicsX = style.BorderColor
+	borderCell := styleCell(style, ' ')
+	if style.BorderColor.IsSet() {
+		borderCell.color = style.BorderColor
 	}
 
 	if style.BorderType != None { // Parsing the style
 		switch style.BorderType {
 		case ContinuousLine:
-			basicsX += "-"
-			basicsY += "|"
+			basicsX = '-'
+			basicsY = '|'
 			break
 		case DottedLine:
-			basicsX += "."
-			basicsY += "."
+			basicsX = '.'
+			basicsY = '.'
 		}
 	}
 
-	if style.BorderColor != "" && style.Color != "" {
-		basicsY += style.Color
-		basicsX += style.Color
-	}
-
-	endLinePositionY := qlYEnd - 1
-	endLinePositionX := qlXEnd - 1
-	textIndex := 0
-	for i := yStart; i < yEnd; i++ { //render y
-		globalBuf.WriteString(setCursorPosition(uint32(xStart)+1, uint32(i)+1)) //set cursor
-		for k := xStart; k < xEnd; k++ {                                        //render x
-			if (i == qlYStart || i == endLinePositionY) && style.BorderType != None {
-				globalBuf.WriteString(basicsX)
-			} else if (k == qlXStart || k == endLinePositionX) && style.BorderType != None {
-				globalBuf.WriteString(basicsY)
-			} else if style.ShowText && textIndex < len(ql.text) {
-				globalBuf.WriteByte(ql.text[textIndex])
-				textIndex++
+	for i := visible.top; i < visible.bottom; i++ { //render y
+		for k := visible.left; k < visible.right; k++ { //render x
+			if (i == bounds.top || i == bounds.bottom-1) && style.BorderType != None {
+				borderCell.char = basicsX
+				screenBuffer.set(k, i, borderCell, visible)
+			} else if (k == bounds.left || k == bounds.right-1) && style.BorderType != None {
+				borderCell.char = basicsY
+				screenBuffer.set(k, i, borderCell, visible)
 			} else {
-				globalBuf.WriteByte(' ')
+				screenBuffer.set(k, i, styleCell(style, ' '), visible)
 			}
 		}
 	}
-	if style.AutoSize { // Re-place after dynamic calculation of width and height
-		ql.volume = oldVolume
-	}
+}
 
-	globalBuf.WriteString(closeAllProperties)
+// textDrawing paints the text of a node, the text flows through the content from the top left corner and is clipped with the children,
+// so it is truncated by hidden axes, scrolled by scrolling axes and spills out of the node on visible axes
+// @parma ql: target rendered quadrilateral content: the area the text flows through
+func textDrawing(ql *Quadrilateral, content canvasRect) {
+	textRunes := ql.spans.runes()
+	clip := ql.childClip
+	lineWidth := content.width()
+	if ql.resolveOverflow().Horizontal == OverflowVisible { // The text is not wrapped when it may spill sideways
+		lineWidth = maxInt(lineWidth, len(textRunes))
+	}
+	if lineWidth <= 0 {
+		return
+	}
 
-	return true
+	start := maxInt(clip.top-content.top, 0) * lineWidth // Lines above the clip are skipped
+	for index := start; index < len(textRunes); index++ {
+		y := content.top + index/lineWidth
+		if y >= clip.bottom {
+			break
+		}
+		textRune := textRunes[index]
+		screenBuffer.set(content.left+index%lineWidth, y, spanCell(ql.style, ql.spans[textRune.span], textRune.char), clip)
+	}
 }
 
-// confirmStartSquare auxiliary function, which assists the squareDrawing function to determine the rendering starting point
-// @parma positionX: start of the current element, start: start of the renderable element
-func confirmStartSquare(positionX int, start int) int {
-	if (positionX < start || positionX <= 0) && start <= 0 {
-		return 0
-	} else if positionX < start && start > 0 {
-		return start
-	} else {
-		return positionX
+// confirmSquareBounds determines the rectangle that a node occupies on the screen, the node is pinned to the content area of its parent by its anchor,
+// nodes placed by the layout of their parent use the computed rectangle
+// @parma canvas: target node
+// @return the absolute rectangle of the node
+func confirmSquareBounds(canvas *Canvas) canvasRect {
+	if canvas.layoutManaged { // The layout of the parent has already placed the node
+		return canvas.layoutRect
 	}
-}
 
-// confirmEndSquare auxiliary function, which assists the squareDrawing function in determining the rendering endpoint
-// @parma positionX: end point of the current element, start: end point of the render
-func confirmEndSquare(positionX int, end int) int {
-	if (positionX > end || positionX >= SysWidth) && end >= SysWidth {
-		return SysWidth
-	} else if positionX > end && end < SysWidth {
-		return end
-	} else {
-		return positionX
+	position := canvas.position
+	anchor := position.Anchor
+	parentRect := screenRect()
+	if canvas.parent != nil {
+		parentRect = canvas.parent.getCanvas().childArea
 	}
-}
 
-// confirmSquareCenter auxiliary function, which assists the squareDrawing function to determine the relative rendering starting point
-// @parma pCount: offset of the current node, width: size of the canvas that can be rendered
-func confirmSquareCenter(pCount, size int) int {
-	return pCount/2 - size/2
-}
+	volume := CanvasVolume{} // Resolve the units of the volume against the parent
+	volume.Width, volume.Height = resolveVolume(canvas, parentRect, parentRect.left+position.X, parentRect.top+position.Y)
 
-// confirmSquareEnd auxiliary function, which assists the squareDrawing function to determine the relative End rendering starting point
-// @parma pCount: offset of the current node, width: size of the canvas that can be rendered
-func confirmSquareEnd(pCount, size int) int {
-	return size - pCount
+	xStart, width := confirmAnchor(anchor.Horizontal, parentRect.left, parentRect.right, position.X, anchor.Right, volume.Width)
+	yStart, height := confirmAnchor(anchor.Vertical, parentRect.top, parentRect.bottom, position.Y, anchor.Bottom, volume.Height)
+	if anchor.Horizontal == AnchorStretch {
+		width = clampLength(width, canvas.volume.MinWidth, canvas.volume.MaxWidth)
+	}
+	if anchor.Vertical == AnchorStretch {
+		height = clampLength(height, canvas.volume.MinHeight, canvas.volume.MaxHeight)
+	}
+
+	return canvasRect{left: xStart, top: yStart, right: xStart + width, bottom: yStart + height}
 }
 
-// getCanvasSize Gets the size of the canvas that can be rendered by the current node, usually the size of its parent
-// @parma node: indicates the node to be obtained
-// @return Wide, high
-func getCanvasSize(node Node) (int, int) {
-	parent, _ := node.GetParent()
-	if parent == nil {
-		return SysWidth, SysHeight
-	}
-	style, _ := parent.GetStyle()
-	volume, _ := parent.GetVolume()
-	if style.AutoSize {
-		return getCanvasSize(parent)
-	} else {
-		return volume.Width, volume.Height
+// confirmAnchor auxiliary function, which assists the confirmSquareBounds function to place one axis of a node by its anchor
+// @parma anchor: anchor of the axis start: start of the parent end: end of the parent offset: offset of the node endOffset: distance from the end used by AnchorStretch size: size of the node
+// @return absolute start and size of the node on the axis
+func confirmAnchor(anchor uint8, start, end, offset, endOffset, size int) (int, int) {
+	switch anchor {
+	case AnchorCenter:
+		return start + (end-start)/2 - size/2 + offset, size
+	case AnchorEnd:
+		return end - offset - size, size
+	case AnchorStretch:
+		return start + offset, maxInt(end-endOffset-start-offset, 0)
 	}
+	return start + offset, size
 }